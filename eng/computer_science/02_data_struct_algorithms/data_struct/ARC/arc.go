@@ -0,0 +1,335 @@
+/*
+ARC (Adaptive Replacement Cache)
+
+What is it?
+ARC is an eviction policy that balances between "recently used" and "frequently used" on its own.
+It keeps two LRU lists of cached entries and two "ghost" lists that remember only the keys of recently evicted entries.
+
+Why is it needed?
+- LRU is not scan-resistant: one pass over many one-off keys (a full table scan, a crawler) evicts the whole working set.
+- LFU resists scans but adapts slowly when popularity changes and needs counters.
+- ARC resists scans like LFU and adapts to the workload like LRU, with the same O(1) cost and no parameters to tune.
+
+What's the point?
+- T1 — entries seen once recently (recency).
+- T2 — entries seen at least twice recently (frequency).
+- B1 — ghost keys evicted from T1, B2 — ghost keys evicted from T2 (no values, only the history).
+- p — the target size of T1. A hit in B1 means "T1 was too small" and p grows,
+  a hit in B2 means "T2 was too small" and p shrinks.
+
+When to use?
+- General-purpose caches with mixed workloads: database buffer pools, storage controllers, page caches.
+- When scans and one-off requests must not wipe out the working set.
+- When there is no way to choose LRU vs LFU in advance.
+
+How does it work?
+- Get of an entry in T1 or T2 moves it to the front of T2 (it is now used at least twice).
+- Set of a new key:
+  - the key is in B1: p += max(|B2|/|B1|, 1), the key goes straight to T2;
+  - the key is in B2: p -= max(|B1|/|B2|, 1), the key goes straight to T2;
+  - otherwise the key goes to the front of T1.
+- When the cache is full, replace evicts the back of T1 (if |T1| > p) or the back of T2,
+  and keeps the evicted key in B1 or B2.
+- The ghost lists are bounded: |T1| + |B1| <= c and |T1| + |T2| + |B1| + |B2| <= 2c.
+- All four lists are lru.List from data_struct/LRU, one hash table points to the key in any of them.
+
+### Complexity
+
+| Operation | Time Complexity (O) | Space Complexity (O) |
+|:---|:---:|:---:|
+| Get | O(1) | O(1) |
+| Set (insertion/update) | O(1) | O(1) |
+| Deletion | O(1) | O(1) |
+| Storage | — | O(c) values + O(c) ghost keys |
+
+*c — the capacity of the cache.
+
+How to know if a problem fits ARC?
+- The hit ratio of LRU drops sharply during scans or batch jobs.
+- The workload changes between recency-heavy and frequency-heavy phases.
+
+ARC[K, V] implements the common cache.Cache interface (see data_struct/cache) and is safe for concurrent use.
+
+Examples of using ARC:
+See the example.go file.
+*/
+
+package arc
+
+import (
+	"context"
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/LRU"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/stats"
+)
+
+// where - the list an entry belongs to
+type where int
+
+const (
+	t1 where = iota // Cached, seen once
+	t2              // Cached, seen at least twice
+	b1              // Ghost, evicted from t1
+	b2              // Ghost, evicted from t2
+)
+
+// entry - a key with its value; ghost entries keep only the key
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	list  where
+}
+
+// ARC - an adaptive replacement cache
+type ARC[K comparable, V any] struct {
+	mu        sync.Mutex
+	capacity  int
+	p         int                              // Target size of t1
+	data      map[K]*lru.Element[*entry[K, V]] // Key -> element of one of the four lists
+	lists     [4]*lru.List[*entry[K, V]]       // t1, t2, b1, b2; front - most recently used
+	onEvict   func(key K, value V)
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+var _ cache.Cache[string, int] = (*ARC[string, int])(nil)
+
+// Option - configures an ARC cache
+type Option[K comparable, V any] func(*ARC[K, V])
+
+// WithOnEvict - sets a callback for entries evicted because of the capacity
+func WithOnEvict[K comparable, V any](fn func(key K, value V)) Option[K, V] {
+	return func(c *ARC[K, V]) {
+		c.onEvict = fn
+	}
+}
+
+// New - creates a new ARC cache with a given capacity (at least 1)
+func New[K comparable, V any](capacity int, opts ...Option[K, V]) *ARC[K, V] {
+	capacity = max(capacity, 1)
+
+	c := &ARC[K, V]{
+		capacity: capacity,
+		data:     make(map[K]*lru.Element[*entry[K, V]], 2*capacity),
+	}
+	for i := range c.lists {
+		c.lists[i] = lru.NewList[*entry[K, V]]()
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Get - returns the value by key; a hit promotes the entry to T2
+func (c *ARC[K, V]) Get(ctx context.Context, key K) (V, error) {
+	var zero V
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.data[key]
+	if !ok || el.Value.list == b1 || el.Value.list == b2 {
+		c.misses++
+		return zero, cache.ErrNotFound
+	}
+
+	c.hits++
+	c.move(el, t2)
+	return el.Value.value, nil
+}
+
+// Peek - returns the value by key without changing the lists
+func (c *ARC[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.data[key]; ok && (el.Value.list == t1 || el.Value.list == t2) {
+		return el.Value.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Set - adds or updates an item, adapting p on ghost hits
+func (c *ARC[K, V]) Set(ctx context.Context, key K, value V) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+
+	var victims []*entry[K, V]
+	el, ok := c.data[key]
+
+	switch {
+	case ok && (el.Value.list == t1 || el.Value.list == t2):
+		el.Value.value = value
+		c.move(el, t2)
+
+	case ok && el.Value.list == b1:
+		// T1 was too small: the key would have been a hit with a bigger T1
+		c.p = min(c.capacity, c.p+max(c.len(b2)/c.len(b1), 1))
+		victims = c.replace(false)
+		el.Value.value = value
+		c.move(el, t2)
+
+	case ok && el.Value.list == b2:
+		// T2 was too small
+		c.p = max(0, c.p-max(c.len(b1)/c.len(b2), 1))
+		victims = c.replace(true)
+		el.Value.value = value
+		c.move(el, t2)
+
+	default:
+		victims = c.admit()
+		c.data[key] = c.lists[t1].PushFront(&entry[K, V]{key: key, value: value, list: t1})
+	}
+
+	c.evictions += uint64(len(victims))
+	c.mu.Unlock()
+
+	c.notify(victims)
+	return nil
+}
+
+// Delete - removes an item from the cache
+func (c *ARC[K, V]) Delete(ctx context.Context, key K) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.Remove(key)
+	return nil
+}
+
+// Remove - removes an item and its ghost, reports whether the item was cached (OnEvict is not called)
+func (c *ARC[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.data[key]
+	if !ok {
+		return false
+	}
+
+	cached := el.Value.list == t1 || el.Value.list == t2
+	c.lists[el.Value.list].Remove(el)
+	delete(c.data, key)
+
+	return cached
+}
+
+// Len - returns the number of cached items (ghosts are not counted)
+func (c *ARC[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.len(t1) + c.len(t2)
+}
+
+// Target - returns the current target size of T1 (the adaptation parameter p)
+func (c *ARC[K, V]) Target() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.p
+}
+
+// Stats - returns the hit, miss and eviction counters and the size (ghosts are not counted)
+func (c *ARC[K, V]) Stats() stats.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return stats.Stats{Hits: c.hits, Misses: c.misses, Evictions: c.evictions, Size: c.len(t1) + c.len(t2)}
+}
+
+// admit - makes room for a key seen for the first time (c.mu must be held)
+func (c *ARC[K, V]) admit() []*entry[K, V] {
+	var victims []*entry[K, V]
+
+	if c.len(t1)+c.len(b1) >= c.capacity {
+		if c.len(t1) < c.capacity {
+			// L1 is full because of ghosts: forget the oldest one and replace
+			c.drop(b1)
+			victims = c.replace(false)
+		} else {
+			// T1 alone fills the cache: evict its oldest entry without a ghost
+			victims = append(victims, c.drop(t1))
+		}
+		return victims
+	}
+
+	if c.len(t1)+c.len(t2)+c.len(b1)+c.len(b2) >= c.capacity {
+		if c.len(t1)+c.len(t2)+c.len(b1)+c.len(b2) >= 2*c.capacity {
+			c.drop(b2)
+		}
+		victims = c.replace(false)
+	}
+
+	return victims
+}
+
+// replace - evicts one cached entry into its ghost list if the cache is full (c.mu must be held)
+func (c *ARC[K, V]) replace(inB2 bool) []*entry[K, V] {
+	if c.len(t1)+c.len(t2) < c.capacity {
+		return nil
+	}
+
+	from, to := t2, b2
+	if c.len(t1) > 0 && (c.len(t1) > c.p || (inB2 && c.len(t1) == c.p) || c.len(t2) == 0) {
+		from, to = t1, b1
+	}
+
+	el := c.lists[from].Back()
+	victim := *el.Value
+
+	var zero V
+	el.Value.value = zero // Ghosts do not hold values
+	c.move(el, to)
+
+	return []*entry[K, V]{&victim}
+}
+
+// drop - removes the oldest entry of a list completely (c.mu must be held)
+func (c *ARC[K, V]) drop(list where) *entry[K, V] {
+	e := c.lists[list].Remove(c.lists[list].Back())
+	delete(c.data, e.key)
+	return e
+}
+
+// move - moves an element to the front of another (or the same) list (c.mu must be held)
+func (c *ARC[K, V]) move(el *lru.Element[*entry[K, V]], to where) {
+	e := el.Value
+	if e.list == to {
+		c.lists[to].MoveToFront(el)
+		return
+	}
+
+	c.lists[e.list].Remove(el)
+	e.list = to
+	c.data[e.key] = c.lists[to].PushFront(e)
+}
+
+// len - returns the length of a list (c.mu must be held)
+func (c *ARC[K, V]) len(list where) int {
+	return c.lists[list].Len()
+}
+
+// notify - calls OnEvict for every victim (must be called without c.mu)
+func (c *ARC[K, V]) notify(victims []*entry[K, V]) {
+	if c.onEvict == nil {
+		return
+	}
+	for _, v := range victims {
+		c.onEvict(v.key, v.value)
+	}
+}
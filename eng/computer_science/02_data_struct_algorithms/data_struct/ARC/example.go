@@ -0,0 +1,41 @@
+package arc
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Example demonstrates the use of an ARC cache
+func Example() {
+	ctx := context.Background()
+
+	arcCache := New(4, WithOnEvict(func(key string, value int) {
+		fmt.Printf("  evicted %s=%d\n", key, value)
+	}))
+
+	// The working set: a and b are used twice, so they move to T2
+	for _, key := range []string{"a", "b"} {
+		arcCache.Set(ctx, key, len(key))
+		arcCache.Get(ctx, key)
+	}
+
+	// A scan of one-off keys only churns T1, the keys in T2 survive
+	fmt.Println("scan of 10 keys:")
+	for i := 0; i < 10; i++ {
+		arcCache.Set(ctx, "scan:"+strconv.Itoa(i), i)
+	}
+
+	for _, key := range []string{"a", "b"} {
+		_, err := arcCache.Get(ctx, key)
+		fmt.Printf("Get(%s) after the scan: hit=%t\n", key, err == nil)
+	}
+
+	// Re-requesting a key evicted from T1 (a ghost in B1) makes T1 bigger
+	fmt.Printf("target size of T1 before: %d\n", arcCache.Target())
+	arcCache.Set(ctx, "scan:7", 7)
+	fmt.Printf("target size of T1 after a B1 ghost hit: %d\n", arcCache.Target())
+
+	stats := arcCache.Stats()
+	fmt.Printf("Len: %d, hits: %d, misses: %d\n", arcCache.Len(), stats.Hits, stats.Misses)
+}
@@ -0,0 +1,84 @@
+package lfu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/LRU"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+)
+
+// Example demonstrates the use of an LFU cache
+func Example() {
+	ctx := context.Background()
+
+	// Create an LFU cache with a capacity of 2 that reports evictions
+	lfuCache := New(2, WithOnEvict(func(key, value int) {
+		fmt.Printf("  evicted %d=%d\n", key, value)
+	}))
+
+	lfuCache.Set(ctx, 1, 1) // freq(1) = 1
+	lfuCache.Set(ctx, 2, 2) // freq(2) = 1
+
+	// Key 1 is used twice more
+	lfuCache.Get(ctx, 1)
+	lfuCache.Get(ctx, 1)
+	fmt.Printf("freq(1) = %d, freq(2) = %d\n", lfuCache.Frequency(1), lfuCache.Frequency(2))
+
+	// Key 2 has the lowest frequency, so it is evicted even though it is more recent than 1
+	fmt.Println("Set(3, 3) - evicts key 2")
+	lfuCache.Set(ctx, 3, 3)
+
+	_, err := lfuCache.Get(ctx, 2)
+	fmt.Printf("Get(2) not found: %t\n", errors.Is(err, cache.ErrNotFound))
+
+	// Ties are broken by recency: 3 and 4 both have freq 1, 3 is older
+	lfuCache.Set(ctx, 4, 4)
+	fmt.Printf("Get(3) not found: %t\n", func() bool {
+		_, err := lfuCache.Get(ctx, 3)
+		return errors.Is(err, cache.ErrNotFound)
+	}())
+
+	stats := lfuCache.Stats()
+	fmt.Printf("Hits: %d, misses: %d\n", stats.Hits, stats.Misses)
+
+	// A frequency-skewed workload: a few hot keys and a scan of one-off keys.
+	// LRU loses the hot keys during every scan, LFU keeps them.
+	fmt.Println("\nHot keys + scans, capacity 10:")
+	fmt.Printf("  LRU hit ratio: %.2f\n", hitRatio(lru.New[string, int](10)))
+	fmt.Printf("  LFU hit ratio: %.2f\n", hitRatio(New[string, int](10)))
+}
+
+// hitRatio - replays a workload of 5 hot keys (each read 3 times per round) interleaved with scans of 20 unique keys
+func hitRatio(c cache.Cache[string, int]) float64 {
+	ctx := context.Background()
+
+	var hits, total int
+	scan := 0
+	for round := 0; round < 100; round++ {
+		for i := 0; i < 15; i++ {
+			key := "hot:" + strconv.Itoa(i%5)
+			total++
+			if _, err := c.Get(ctx, key); err == nil {
+				hits++
+			} else {
+				c.Set(ctx, key, i%5)
+			}
+		}
+
+		for i := 0; i < 20; i++ {
+			key := "scan:" + strconv.Itoa(scan)
+			scan++
+			total++
+			if _, err := c.Get(ctx, key); err == nil {
+				hits++
+			} else {
+				c.Set(ctx, key, i)
+			}
+		}
+	}
+
+	return float64(hits) / float64(total)
+}
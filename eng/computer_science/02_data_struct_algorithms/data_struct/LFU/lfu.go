@@ -0,0 +1,280 @@
+/*
+LFU Cache (Least Frequently Used Cache)
+
+What is it?
+An LFU cache limits the number of items and, when the limit is reached, removes the item that was used
+the fewest times. If several items have the same (lowest) count, the least recently used of them goes first.
+
+Why is it needed?
+- Some workloads are skewed by frequency: a small set of keys is requested all the time,
+  while many other keys appear once and never again.
+- LRU evicts a popular key as soon as enough one-off keys pass through the cache (thrashing).
+  LFU keeps the popular keys because their counters are high.
+
+What's the point?
+- Every item has a use counter (frequency).
+- Eviction removes an item with the minimal frequency.
+- Ties are broken by recency (LRU inside the same frequency).
+
+When to use?
+- The popularity of keys is stable over time (catalogs, configuration, reference data).
+- The access pattern is frequency-skewed (Zipf-like).
+- Not the best choice when popularity changes quickly: old counters keep stale keys alive.
+
+How does it work (the O(1) design)?
+- A doubly linked list of frequency buckets, ordered by frequency: the front bucket has the lowest count.
+- Each bucket holds its own doubly linked list of items with that count; the front item is the most recent.
+- A hash table maps a key to its item, and every item knows its bucket.
+- On access, the item moves from bucket f to bucket f+1 (created right after f if it does not exist);
+  an empty bucket is removed. All of these are pointer changes — O(1).
+- To evict, take the back item (least recent) of the front bucket (lowest frequency) — O(1).
+- Both kinds of lists are lru.List from data_struct/LRU.
+
+### Complexity
+
+| Operation | Time Complexity (O) | Space Complexity (O) |
+|:---|:---:|:---:|
+| Get | O(1) | O(1) |
+| Set (insertion/update) | O(1) | O(1) |
+| Eviction | O(1) | O(1) |
+| Storage | — | O(n) |
+
+How to know if a problem fits LFU Cache?
+- The cache must keep the "most popular" items, not the "most recent" ones.
+- Scans or one-off requests must not wipe out frequently used data.
+
+LFUCache[K, V] implements the common cache.Cache interface (see data_struct/cache) and is safe for concurrent use.
+
+Examples of using LFU Cache:
+See the example.go file.
+*/
+
+package lfu
+
+import (
+	"context"
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/LRU"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/stats"
+)
+
+// entry - a cached item that knows the frequency bucket it belongs to
+type entry[K comparable, V any] struct {
+	key    K
+	value  V
+	bucket *lru.Element[*bucket[K, V]]
+}
+
+// bucket - all items with the same use counter, from the most to the least recently used
+type bucket[K comparable, V any] struct {
+	freq  int
+	items *lru.List[*entry[K, V]]
+}
+
+// LFUCache - an O(1) LFU cache with LRU tie-breaking
+type LFUCache[K comparable, V any] struct {
+	mu        sync.Mutex
+	capacity  int
+	data      map[K]*lru.Element[*entry[K, V]] // Hash table for fast access
+	buckets   *lru.List[*bucket[K, V]]         // Frequency buckets, front - the lowest frequency
+	onEvict   func(key K, value V)
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+var _ cache.Cache[string, int] = (*LFUCache[string, int])(nil)
+
+// Option - configures an LFU cache
+type Option[K comparable, V any] func(*LFUCache[K, V])
+
+// WithOnEvict - sets a callback for entries evicted because of the capacity
+func WithOnEvict[K comparable, V any](fn func(key K, value V)) Option[K, V] {
+	return func(c *LFUCache[K, V]) {
+		c.onEvict = fn
+	}
+}
+
+// New - creates a new LFU cache with a given capacity (at least 1)
+func New[K comparable, V any](capacity int, opts ...Option[K, V]) *LFUCache[K, V] {
+	capacity = max(capacity, 1)
+
+	c := &LFUCache[K, V]{
+		capacity: capacity,
+		data:     make(map[K]*lru.Element[*entry[K, V]], capacity),
+		buckets:  lru.NewList[*bucket[K, V]](),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Get - returns the value by key and increments its use counter
+func (c *LFUCache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	var zero V
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.data[key]
+	if !ok {
+		c.misses++
+		return zero, cache.ErrNotFound
+	}
+
+	c.hits++
+	c.touch(el)
+	return el.Value.value, nil
+}
+
+// Peek - returns the value by key without counting the access
+func (c *LFUCache[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.data[key]; ok {
+		return el.Value.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Set - adds or updates an item; an update counts as a use
+func (c *LFUCache[K, V]) Set(ctx context.Context, key K, value V) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+
+	if el, ok := c.data[key]; ok {
+		el.Value.value = value
+		c.touch(el)
+		c.mu.Unlock()
+		return nil
+	}
+
+	// Free a place before inserting, so the new item is never the victim
+	var victim *entry[K, V]
+	if len(c.data) >= c.capacity {
+		victim = c.evict()
+	}
+
+	// A new item always starts in the bucket with frequency 1 at the front
+	first := c.buckets.Front()
+	if first == nil || first.Value.freq != 1 {
+		first = c.buckets.PushFront(&bucket[K, V]{freq: 1, items: lru.NewList[*entry[K, V]]()})
+	}
+
+	e := &entry[K, V]{key: key, value: value, bucket: first}
+	c.data[key] = first.Value.items.PushFront(e)
+	c.mu.Unlock()
+
+	if victim != nil && c.onEvict != nil {
+		c.onEvict(victim.key, victim.value)
+	}
+	return nil
+}
+
+// Delete - removes an item from the cache
+func (c *LFUCache[K, V]) Delete(ctx context.Context, key K) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.Remove(key)
+	return nil
+}
+
+// Remove - removes an item, reports whether it was present (OnEvict is not called)
+func (c *LFUCache[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.data[key]
+	if !ok {
+		return false
+	}
+
+	c.unlink(el)
+	delete(c.data, key)
+	return true
+}
+
+// Frequency - returns the use counter of a key (0 if it is not cached)
+func (c *LFUCache[K, V]) Frequency(key K) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.data[key]; ok {
+		return el.Value.bucket.Value.freq
+	}
+	return 0
+}
+
+// Len - returns the number of items in the cache
+func (c *LFUCache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.data)
+}
+
+// Stats - returns the hit, miss and eviction counters and the size
+func (c *LFUCache[K, V]) Stats() stats.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return stats.Stats{Hits: c.hits, Misses: c.misses, Evictions: c.evictions, Size: len(c.data)}
+}
+
+// touch - moves an item from its bucket f to the bucket f+1 (c.mu must be held)
+func (c *LFUCache[K, V]) touch(el *lru.Element[*entry[K, V]]) {
+	e := el.Value
+	current := e.bucket
+
+	next := current.Next()
+	if next == nil || next.Value.freq != current.Value.freq+1 {
+		next = c.buckets.InsertAfter(&bucket[K, V]{
+			freq:  current.Value.freq + 1,
+			items: lru.NewList[*entry[K, V]](),
+		}, current)
+	}
+
+	c.unlink(el)
+	e.bucket = next
+	c.data[e.key] = next.Value.items.PushFront(e)
+}
+
+// unlink - removes an item from its bucket and drops the bucket if it becomes empty (c.mu must be held)
+func (c *LFUCache[K, V]) unlink(el *lru.Element[*entry[K, V]]) {
+	b := el.Value.bucket
+
+	b.Value.items.Remove(el)
+	if b.Value.items.Len() == 0 {
+		c.buckets.Remove(b)
+	}
+}
+
+// evict - removes the least recently used item of the lowest frequency (c.mu must be held)
+func (c *LFUCache[K, V]) evict() *entry[K, V] {
+	lowest := c.buckets.Front()
+	if lowest == nil {
+		return nil
+	}
+
+	victim := lowest.Value.items.Back()
+	c.unlink(victim)
+	delete(c.data, victim.Value.key)
+	c.evictions++
+
+	return victim.Value
+}
@@ -1,39 +1,124 @@
 package lru
 
-import "fmt"
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+)
 
 // Example demonstrates the use of an LRU cache
 func Example() {
-	// Create an LRU cache with a capacity of 2
-	cache := New(2)
+	ctx := context.Background()
+
+	// Create an LRU cache with a capacity of 2 that reports evictions
+	lruCache := New(2, WithOnEvict(func(key, value int) {
+		fmt.Printf("  evicted %d=%d\n", key, value)
+	}))
 
 	// Add elements
-	cache.Put(1, 1) // cache: [1=1]
-	fmt.Println("Put(1, 1)")
+	lruCache.Set(ctx, 1, 1) // cache: [1=1]
+	fmt.Println("Set(1, 1)")
 
-	cache.Put(2, 2) // cache: [2=2, 1=1]
-	fmt.Println("Put(2, 2)")
+	lruCache.Set(ctx, 2, 2) // cache: [2=2, 1=1]
+	fmt.Println("Set(2, 2)")
 
 	// Retrieve an element
-	val := cache.Get(1)
+	val, _ := lruCache.Get(ctx, 1)
 	fmt.Printf("Get(1): %d (expected 1)\n", val) // cache: [1=1, 2=2]
 
 	// Add another item (triggers deletion of the least used — 2)
-	cache.Put(3, 3) // cache: [3=3, 1=1]
-	fmt.Println("Put(3, 3) - evicts key 2")
+	fmt.Println("Set(3, 3) - evicts key 2")
+	lruCache.Set(ctx, 3, 3) // cache: [3=3, 1=1]
 
-	val = cache.Get(2)
-	fmt.Printf("Get(2): %d (expected -1)\n", val) // 2 was removed
+	_, err := lruCache.Get(ctx, 2)
+	fmt.Printf("Get(2): %v (expected %v)\n", err, cache.ErrNotFound) // 2 was removed
 
-	cache.Put(4, 4) // cache: [4=4, 3=3] - evicts 1
-	fmt.Println("Put(4, 4) - evicts key 1")
+	// Peek does not change the order: 1 stays the least recently used
+	val, _ = lruCache.Peek(1)
+	fmt.Printf("Peek(1): %d, keys by recency: %v\n", val, lruCache.Keys())
 
-	val = cache.Get(1)
-	fmt.Printf("Get(1): %d (expected -1)\n", val)
+	fmt.Println("Set(4, 4) - evicts key 1")
+	lruCache.Set(ctx, 4, 4) // cache: [4=4, 3=3] - evicts 1
 
-	val = cache.Get(3)
+	_, err = lruCache.Get(ctx, 1)
+	fmt.Printf("Get(1) not found: %t\n", errors.Is(err, cache.ErrNotFound))
+
+	val, _ = lruCache.Get(ctx, 3)
 	fmt.Printf("Get(3): %d (expected 3)\n", val)
 
-	val = cache.Get(4)
+	val, _ = lruCache.Get(ctx, 4)
 	fmt.Printf("Get(4): %d (expected 4)\n", val)
+
+	// Shrinking evicts the least recently used key
+	fmt.Println("Resize(1)")
+	lruCache.Resize(1)
+
+	stats := lruCache.Stats()
+	fmt.Printf("Hits: %d, misses: %d, hit ratio: %.2f\n", stats.Hits, stats.Misses, stats.HitRatio())
+
+	// Used through the common interface from many goroutines
+	var sessions cache.Cache[string, string] = New[string, string](100)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sessions.Set(ctx, "session:"+strconv.Itoa(i), "user"+strconv.Itoa(i))
+		}(i)
+	}
+	wg.Wait()
+
+	user, _ := sessions.Get(ctx, "session:7")
+	fmt.Printf("session:7 belongs to %s\n", user)
+
+	// Snapshot and restore: a restarted service starts warm, with the same recency order
+	warm := New[int, int](3)
+	for i := 1; i <= 3; i++ {
+		warm.Set(ctx, i, i*i)
+	}
+	warm.Get(ctx, 1) // Order: 1, 3, 2
+
+	var snap bytes.Buffer
+	warm.Snapshot(&snap)
+
+	restarted := New[int, int](3)
+	err = restarted.Restore(&snap)
+	fmt.Printf("restored keys by recency: %v, err: %v\n", restarted.Keys(), err)
+
+	// A cost budget instead of an entry count: 1 KB of payloads, no limit on the number of entries
+	pages := New[string, []byte](0, WithMaxCost[string, []byte](1024), WithOnEvict(func(key string, value []byte) {
+		fmt.Printf("  evicted %s (%d bytes)\n", key, len(value))
+	}))
+
+	pages.Set(ctx, "/small", make([]byte, 100))
+	pages.Set(ctx, "/medium", make([]byte, 400))
+	pages.Set(ctx, "/large", make([]byte, 500))
+	fmt.Printf("3 pages: %d entries, %d of %d bytes\n", pages.Len(), pages.Cost(), pages.MaxCost())
+
+	// 300 more bytes do not fit: the oldest pages are evicted until the budget is satisfied
+	fmt.Println("Set(/new, 300 bytes)")
+	pages.Set(ctx, "/new", make([]byte, 300))
+	fmt.Printf("keys: %v, %d bytes\n", pages.Keys(), pages.Cost())
+
+	// A value bigger than the whole budget is rejected instead of flushing the cache
+	err = pages.Set(ctx, "/huge", make([]byte, 4096))
+	fmt.Printf("Set(/huge, 4096 bytes): %v, keys: %v\n", err, pages.Keys())
+
+	// A custom cost function: the cost of a string slice is the sum of its lengths
+	lists := New[string, []string](0, WithMaxCost[string, []string](10), WithCost[string](func(v []string) int64 {
+		var n int64
+		for _, s := range v {
+			n += int64(len(s))
+		}
+		return n
+	}))
+	lists.Set(ctx, "a", []string{"abc", "de"})
+	lists.Set(ctx, "b", []string{"fghij"})
+	fmt.Printf("lists: %d entries, cost %d\n", lists.Len(), lists.Cost())
 }
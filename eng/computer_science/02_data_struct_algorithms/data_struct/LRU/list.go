@@ -0,0 +1,116 @@
+package lru
+
+// Element - a node of the doubly linked List
+type Element[T any] struct {
+	Value T
+	next  *Element[T]
+	prev  *Element[T]
+	list  *List[T]
+}
+
+// Next - returns the next element or nil at the end of the list
+func (e *Element[T]) Next() *Element[T] {
+	if n := e.next; e.list != nil && n != &e.list.root {
+		return n
+	}
+	return nil
+}
+
+// Prev - returns the previous element or nil at the beginning of the list
+func (e *Element[T]) Prev() *Element[T] {
+	if p := e.prev; e.list != nil && p != &e.list.root {
+		return p
+	}
+	return nil
+}
+
+// List - a doubly linked list with one virtual node that is both the head and the tail.
+// The LRU cache keeps its recency order in it; other caches (LFU, ARC, 2Q) reuse it.
+// All operations are O(1). The list is not thread-safe: the cache that owns it holds the lock.
+type List[T any] struct {
+	root Element[T] // root.next is the front, root.prev is the back
+	len  int
+}
+
+// NewList - creates an empty list
+func NewList[T any]() *List[T] {
+	l := &List[T]{}
+	l.root.next = &l.root
+	l.root.prev = &l.root
+	return l
+}
+
+// Len - returns the number of elements
+func (l *List[T]) Len() int {
+	return l.len
+}
+
+// Front - returns the first element or nil
+func (l *List[T]) Front() *Element[T] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.next
+}
+
+// Back - returns the last element or nil
+func (l *List[T]) Back() *Element[T] {
+	if l.len == 0 {
+		return nil
+	}
+	return l.root.prev
+}
+
+// PushFront - adds a value to the front of the list
+func (l *List[T]) PushFront(v T) *Element[T] {
+	return l.insertAfter(&Element[T]{Value: v}, &l.root)
+}
+
+// PushBack - adds a value to the back of the list
+func (l *List[T]) PushBack(v T) *Element[T] {
+	return l.insertAfter(&Element[T]{Value: v}, l.root.prev)
+}
+
+// InsertAfter - adds a value right after mark (mark must belong to the list)
+func (l *List[T]) InsertAfter(v T, mark *Element[T]) *Element[T] {
+	return l.insertAfter(&Element[T]{Value: v}, mark)
+}
+
+// Remove - removes an element from the list and returns its value
+func (l *List[T]) Remove(e *Element[T]) T {
+	if e.list == l {
+		l.unlink(e)
+	}
+	return e.Value
+}
+
+// MoveToFront - moves an element to the front of the list
+func (l *List[T]) MoveToFront(e *Element[T]) {
+	if e.list != l || l.root.next == e {
+		return
+	}
+
+	l.unlink(e)
+	l.insertAfter(e, &l.root)
+}
+
+// insertAfter - links e right after at
+func (l *List[T]) insertAfter(e, at *Element[T]) *Element[T] {
+	e.prev = at
+	e.next = at.next
+	at.next.prev = e
+	at.next = e
+	e.list = l
+	l.len++
+	return e
+}
+
+// unlink - takes e out of the list
+func (l *List[T]) unlink(e *Element[T]) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	e.next = nil
+	e.prev = nil
+	e.list = nil
+	l.len--
+}
@@ -44,105 +44,324 @@ How to know if a problem fits LRU Cache?
 LRU Cache in Go:
 
 Implementing an LRU cache in Go typically combines:
-- A doubly linked list to track usage order.
+- A doubly linked list to track usage order (List in list.go, also reused by other caches).
 - A hash table (map) for fast element access.
 - Synchronization for multi-threaded access.
 - Memory management for efficiency.
 
+LRU[K, V] in this package:
+- Generic and implements the common cache.Cache interface (see data_struct/cache):
+  a miss is reported with cache.ErrNotFound instead of a magic -1.
+- Safe for concurrent use: every operation takes a mutex (even Get changes the list order).
+- The list and the hash table are private, so the cache cannot be corrupted from outside.
+- An OnEvict callback is called for every entry dropped because of the capacity (after the lock is released,
+  so the callback may use the cache itself).
+- Peek reads a value without making it recently used; Keys lists keys from the most to the least recently used.
+- Resize changes the capacity at runtime and evicts the oldest entries if needed.
+- Hits and misses of Get, evictions, the size and the cost are counted (Stats, see data_struct/cache/stats).
+- Cost budget: values from a few bytes to megabytes should not count the same. WithMaxCost bounds the total cost
+  of the values; WithCost sets how a value is measured (cache.DefaultCost by default: the length of strings and byte slices).
+  Eviction from the tail continues until both the entry limit and the budget are satisfied.
+  A value costing more than WithMaxItemCost (the whole budget by default) is rejected with cache.ErrTooLarge
+  instead of flushing the cache for it.
+- Snapshot and Restore (snapshot.go) save the cache to a versioned binary format and load it back
+  in the same recency order (see data_struct/cache/snapshot).
+
 Examples of using LRU Cache:
 See the example.go file.
 */
 
 package lru
 
-// Node - a doubly linked list node for the LRU cache
-type Node struct {
-	Key   int
-	Value int
-	Next  *Node
-	Prev  *Node
+import (
+	"context"
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/stats"
+)
+
+// entry - a key-value pair stored in a node of the recency list
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	cost  int64
 }
 
-// LRUCache - an LRU cache implementation using a doubly linked list and a hash table
-type LRUCache struct {
-	Capacity int
-	Data     map[int]*Node // Hash table for fast access
-	Head     *Node         // Virtual head of the list
-	Tail     *Node         // Virtual tail of the list
+// LRU - a thread-safe LRU cache implementation using a doubly linked list and a hash table
+type LRU[K comparable, V any] struct {
+	mu          sync.Mutex
+	capacity    int                         // Max entries, 0 - unlimited (only with a cost budget)
+	data        map[K]*Element[entry[K, V]] // Hash table for fast access
+	order       *List[entry[K, V]]          // Front - most recently used, back - least recently used
+	onEvict     func(key K, value V)
+	costFn      func(value V) int64
+	cost        int64 // Total cost of the stored values
+	maxCost     int64 // Cost budget, 0 - unlimited
+	maxItemCost int64 // Max cost of one value, 0 - unlimited (the whole budget if there is one)
+	hits        uint64
+	misses      uint64
+	evictions   uint64
 }
 
-// Constructor - creates a new LRU cache with a given capacity
-func New(capacity int) LRUCache {
-	data := make(map[int]*Node, capacity)
+var _ cache.Cache[string, int] = (*LRU[string, int])(nil)
+
+// Stats - the counters of a cache; kept as an alias so the code written against lru.Stats keeps working
+type Stats = stats.Stats
+
+// Option - configures an LRU cache
+type Option[K comparable, V any] func(*LRU[K, V])
 
-	// Create virtual head and tail to simplify operations
-	head := &Node{}
-	tail := &Node{}
-	head.Next = tail
-	tail.Prev = head
+// WithOnEvict - sets a callback for entries evicted because of the capacity
+func WithOnEvict[K comparable, V any](fn func(key K, value V)) Option[K, V] {
+	return func(c *LRU[K, V]) {
+		c.onEvict = fn
+	}
+}
 
-	return LRUCache{
-		Capacity: capacity,
-		Data:     data,
-		Head:     head,
-		Tail:     tail,
+// WithCost - sets the function that measures a value for the cost budget (cache.DefaultCost by default)
+func WithCost[K comparable, V any](fn func(value V) int64) Option[K, V] {
+	return func(c *LRU[K, V]) {
+		c.costFn = fn
 	}
 }
 
-// remove - removes a node from the doubly linked list
-func (this *LRUCache) remove(node *Node) {
-	node.Prev.Next = node.Next
-	node.Next.Prev = node.Prev
+// WithMaxCost - bounds the total cost of the values; with a budget the capacity may be 0 (no entry limit)
+func WithMaxCost[K comparable, V any](maxCost int64) Option[K, V] {
+	return func(c *LRU[K, V]) {
+		c.maxCost = max(maxCost, 0)
+	}
 }
 
-// addToHead - adds a node to the head of the list (making it recently used)
-func (this *LRUCache) addToHead(node *Node) {
-	node.Next = this.Head.Next
-	node.Prev = this.Head
-	this.Head.Next.Prev = node
-	this.Head.Next = node
+// WithMaxItemCost - rejects values that cost more than n with cache.ErrTooLarge
+// (the whole budget by default; works without WithMaxCost as well)
+func WithMaxItemCost[K comparable, V any](n int64) Option[K, V] {
+	return func(c *LRU[K, V]) {
+		c.maxItemCost = max(n, 0)
+	}
+}
+
+// New - creates a new LRU cache with a given capacity (at least 1 unless a cost budget is set)
+func New[K comparable, V any](capacity int, opts ...Option[K, V]) *LRU[K, V] {
+	c := &LRU[K, V]{
+		data:   make(map[K]*Element[entry[K, V]], max(capacity, 0)),
+		order:  NewList[entry[K, V]](),
+		costFn: cache.DefaultCost[V],
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.capacity = c.clampCapacity(capacity)
+	// The limit of one value cannot exceed the budget; without a budget it applies as set
+	if c.maxCost > 0 && (c.maxItemCost == 0 || c.maxItemCost > c.maxCost) {
+		c.maxItemCost = c.maxCost
+	}
+
+	return c
 }
 
 // Get - returns the value by key, marking the item as recently used
-func (this *LRUCache) Get(key int) int {
-	node, ok := this.Data[key]
+func (c *LRU[K, V]) Get(ctx context.Context, key K) (V, error) {
+	var zero V
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n, ok := c.data[key]
 	if !ok {
-		return -1 // Key not found
+		c.misses++
+		return zero, cache.ErrNotFound // Key not found
 	}
 
 	// Move the node to the head (as recently used)
-	this.remove(node)
-	this.addToHead(node)
-	return node.Value
+	c.hits++
+	c.order.MoveToFront(n)
+	return n.Value.value, nil
+}
+
+// Peek - returns the value by key without marking it as recently used
+func (c *LRU[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if n, ok := c.data[key]; ok {
+		return n.Value.value, true
+	}
+
+	var zero V
+	return zero, false
 }
 
-// Put - adds or updates an item in the cache
-func (this *LRUCache) Put(key int, value int) {
-	node, ok := this.Data[key]
-	if ok {
+// Set - adds or updates an item in the cache.
+// A value over the item cost limit is rejected with cache.ErrTooLarge, and the old value of the key is dropped.
+func (c *LRU[K, V]) Set(ctx context.Context, key K, value V) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cost := c.costFn(value)
+
+	c.mu.Lock()
+
+	if c.maxItemCost > 0 && cost > c.maxItemCost {
+		if n, ok := c.data[key]; ok {
+			c.remove(n)
+		}
+		c.mu.Unlock()
+		return cache.ErrTooLarge
+	}
+
+	if n, ok := c.data[key]; ok {
 		// Update existing item
-		node.Value = value
+		c.cost += cost - n.Value.cost
+		n.Value.value = value
+		n.Value.cost = cost
 		// Move to head as recently used
-		this.remove(node)
-		this.addToHead(node)
-		return
+		c.order.MoveToFront(n)
+	} else {
+		// Add a new node to the head of the list and to the hash table
+		c.data[key] = c.order.PushFront(entry[K, V]{key: key, value: value, cost: cost})
+		c.cost += cost
+	}
+
+	// Remove the least recently used elements while the capacity or the budget is exceeded
+	evicted := c.evict()
+	c.mu.Unlock()
+
+	c.notify(evicted)
+	return nil
+}
+
+// Delete - removes an item from the cache
+func (c *LRU[K, V]) Delete(ctx context.Context, key K) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.Remove(key)
+	return nil
+}
+
+// Remove - removes an item from the cache, reports whether it was present.
+// Removal is not an eviction, so OnEvict is not called.
+func (c *LRU[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n, ok := c.data[key]
+	if !ok {
+		return false
+	}
+
+	c.remove(n)
+	return true
+}
+
+// Keys - returns the keys from the most to the least recently used
+func (c *LRU[K, V]) Keys() []K {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := make([]K, 0, len(c.data))
+	for n := c.order.Front(); n != nil; n = n.Next() {
+		keys = append(keys, n.Value.key)
+	}
+
+	return keys
+}
+
+// Len - returns the number of items in the cache
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.data)
+}
+
+// Cost - returns the total cost of the stored values
+func (c *LRU[K, V]) Cost() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.cost
+}
+
+// MaxCost - returns the cost budget (0 - unlimited)
+func (c *LRU[K, V]) MaxCost() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.maxCost
+}
+
+// Cap - returns the capacity of the cache (0 - no entry limit)
+func (c *LRU[K, V]) Cap() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.capacity
+}
+
+// Resize - changes the capacity (at least 1 unless a cost budget is set),
+// evicting the least recently used items if needed. Returns the number of evicted items.
+func (c *LRU[K, V]) Resize(capacity int) int {
+	c.mu.Lock()
+	c.capacity = c.clampCapacity(capacity)
+	evicted := c.evict()
+	c.mu.Unlock()
+
+	c.notify(evicted)
+	return len(evicted)
+}
+
+// Stats - returns the hit, miss and eviction counters, the size and the cost
+func (c *LRU[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return Stats{Hits: c.hits, Misses: c.misses, Evictions: c.evictions, Size: len(c.data), Cost: c.cost}
+}
+
+// evict - removes items from the tail until both the capacity and the cost budget are satisfied (c.mu must be held).
+// Returns the removed entries so the callback can be called without the lock.
+func (c *LRU[K, V]) evict() []entry[K, V] {
+	var evicted []entry[K, V]
+
+	for c.capacity > 0 && len(c.data) > c.capacity || c.maxCost > 0 && c.cost > c.maxCost {
+		leastUsed := c.order.Back().Value
+		c.remove(c.order.Back())
+		c.evictions++
+		evicted = append(evicted, leastUsed)
 	}
 
-	// Check if capacity is exceeded
-	if len(this.Data) >= this.Capacity {
-		// Remove the least recently used element (at the tail of the list)
-		leastUsed := this.Tail.Prev
-		this.remove(leastUsed)
-		delete(this.Data, leastUsed.Key)
+	return evicted
+}
+
+// remove - removes an element from the list and the hash table and releases its cost (c.mu must be held)
+func (c *LRU[K, V]) remove(n *Element[entry[K, V]]) {
+	e := c.order.Remove(n)
+	delete(c.data, e.key)
+	c.cost -= e.cost
+}
+
+// clampCapacity - the entry limit is at least 1, unless the cache is bounded by a cost budget
+func (c *LRU[K, V]) clampCapacity(capacity int) int {
+	if c.maxCost > 0 {
+		return max(capacity, 0)
 	}
+	return max(capacity, 1)
+}
 
-	// Create a new node
-	newNode := &Node{
-		Key:   key,
-		Value: value,
+// notify - calls OnEvict for the evicted entries (c.mu must NOT be held)
+func (c *LRU[K, V]) notify(evicted []entry[K, V]) {
+	if c.onEvict == nil {
+		return
 	}
 
-	// Add to the head of the list and to the hash table
-	this.addToHead(newNode)
-	this.Data[key] = newNode
+	for _, n := range evicted {
+		c.onEvict(n.key, n.value)
+	}
 }
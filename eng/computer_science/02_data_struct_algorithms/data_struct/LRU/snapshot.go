@@ -0,0 +1,57 @@
+package lru
+
+import (
+	"io"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/snapshot"
+)
+
+// Snapshot - writes all entries from the least to the most recently used (see data_struct/cache/snapshot).
+// Keys and values must be gob-encodable.
+func (c *LRU[K, V]) Snapshot(w io.Writer) error {
+	c.mu.Lock()
+	records := make([]snapshot.Record[K, V], 0, len(c.data))
+	for el := c.order.Back(); el != nil; el = el.Prev() {
+		records = append(records, snapshot.Record[K, V]{Key: el.Value.key, Value: el.Value.value})
+	}
+	c.mu.Unlock()
+
+	return snapshot.Write(w, snapshot.KindLRU, records)
+}
+
+// Restore - replaces the content of the cache with a snapshot, keeping the recency order.
+// If the snapshot has more entries than the capacity or the cost budget allows,
+// only the most recently used ones are kept. The entries the restore drops are not evictions:
+// OnEvict is not called for them and the eviction counter of Stats does not change.
+func (c *LRU[K, V]) Restore(r io.Reader) error {
+	records, err := snapshot.Read[K, V](r, snapshot.KindLRU)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.data = make(map[K]*Element[entry[K, V]], len(records))
+	c.order = NewList[entry[K, V]]()
+	c.cost = 0
+	evictions := c.evictions
+
+	// The records go from the oldest to the newest: each one is pushed in front of the previous ones,
+	// and the oldest ones fall off the tail once the limits are reached
+	for _, rec := range records {
+		if el, ok := c.data[rec.Key]; ok {
+			c.remove(el)
+		}
+
+		cost := c.costFn(rec.Value)
+		if c.maxItemCost > 0 && cost > c.maxItemCost {
+			continue
+		}
+		c.data[rec.Key] = c.order.PushFront(entry[K, V]{key: rec.Key, value: rec.Value, cost: cost})
+		c.cost += cost
+		c.evict() // The dropped entries are returned, not passed to OnEvict
+	}
+	c.evictions = evictions
+	return nil
+}
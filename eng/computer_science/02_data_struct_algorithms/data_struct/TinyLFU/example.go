@@ -0,0 +1,103 @@
+package tinylfu
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/LRU"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+)
+
+// Example demonstrates the use of a W-TinyLFU cache
+func Example() {
+	ctx := context.Background()
+
+	// The same constructor style as lru.New
+	tinyCache := New(100, WithOnEvict(func(key string, value int) {
+		if key == "one-off" {
+			fmt.Printf("  %s rejected by the admission filter\n", key)
+		}
+	}))
+
+	// Make 100 popular keys: each is requested several times
+	for round := 0; round < 3; round++ {
+		for i := 0; i < 100; i++ {
+			key := "popular:" + strconv.Itoa(i)
+			if _, err := tinyCache.Get(ctx, key); err != nil {
+				tinyCache.Set(ctx, key, i)
+			}
+		}
+	}
+
+	// A one-off key passes through the window, then loses the contest against a popular victim
+	tinyCache.Set(ctx, "one-off", -1)
+	tinyCache.Set(ctx, "another one-off", -2)
+
+	fmt.Printf("freq(popular:0) = %d, freq(one-off) = %d\n",
+		tinyCache.Frequency("popular:0"), tinyCache.Frequency("one-off"))
+
+	_, err := tinyCache.Get(ctx, "popular:0")
+	fmt.Printf("popular:0 is still cached: %t\n", err == nil)
+
+	// W-TinyLFU against plain LRU on skewed Zipf workloads
+	fmt.Println("\nZipf workload, 200 000 requests over 20 000 keys:")
+	for _, s := range []float64{0.8, 1.0, 1.2} {
+		keys := zipfKeys(s, 20_000, 200_000)
+		for _, capacity := range []int{200, 2_000} {
+			fmt.Printf("  s=%.1f capacity %-6d LRU %5.2f%%  W-TinyLFU %5.2f%%\n", s, capacity,
+				hitRatio(lru.New[string, struct{}](capacity), keys)*100,
+				hitRatio(New[string, struct{}](capacity), keys)*100)
+		}
+	}
+}
+
+// zipfKeys - generates n requests over distinct keys with the Zipf exponent s
+// (rand.Zipf needs s > 1, so the ranks are drawn by the inverse transform instead)
+func zipfKeys(s float64, distinct, n int) []string {
+	r := rand.New(rand.NewPCG(7, 11))
+
+	weights := make([]float64, distinct)
+	total := 0.0
+	for i := range weights {
+		total += 1 / math.Pow(float64(i+1), s)
+		weights[i] = total
+	}
+
+	keys := make([]string, n)
+	for i := range keys {
+		target := r.Float64() * total
+
+		// Binary search of the rank whose cumulative weight covers the target
+		lo, hi := 0, distinct-1
+		for lo < hi {
+			mid := (lo + hi) / 2
+			if weights[mid] < target {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+		keys[i] = "key:" + strconv.Itoa(lo)
+	}
+
+	return keys
+}
+
+// hitRatio - replays requests against a cache as a read-through cache
+func hitRatio(c cache.Cache[string, struct{}], keys []string) float64 {
+	ctx := context.Background()
+
+	hits := 0
+	for _, key := range keys {
+		if _, err := c.Get(ctx, key); err == nil {
+			hits++
+		} else {
+			c.Set(ctx, key, struct{}{})
+		}
+	}
+
+	return float64(hits) / float64(len(keys))
+}
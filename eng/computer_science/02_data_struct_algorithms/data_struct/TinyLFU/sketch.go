@@ -0,0 +1,78 @@
+package tinylfu
+
+import "math/bits"
+
+const (
+	sketchDepth  = 4                  // Number of rows (hash functions)
+	counterMax   = 15                 // 4-bit counters saturate at 15
+	resetMask    = 0x7777777777777777 // Clears the high bit of every 4-bit counter after a shift
+	sampleFactor = 10                 // Aging happens after sampleFactor * capacity increments
+)
+
+// sketch - a Count-Min Sketch with 4-bit counters and periodic aging.
+// Sixteen counters are packed into one uint64, so the sketch needs only 8 bytes per 16 counters per row.
+// Every row is indexed by its own hash of the key; the estimate is the minimum over the rows,
+// because collisions can only overestimate a counter.
+type sketch struct {
+	rows    [sketchDepth][]uint64
+	mask    uint64 // Number of counters per row minus one (a power of two)
+	added   int    // Increments since the last aging
+	samples int    // Aging period
+}
+
+// newSketch - creates a sketch sized for the given number of cached items
+func newSketch(capacity int) *sketch {
+	counters := uint64(1) << bits.Len(uint(max(capacity, 16)-1)) // Next power of two
+
+	s := &sketch{
+		mask:    counters - 1,
+		samples: sampleFactor * max(capacity, 1),
+	}
+	for i := range s.rows {
+		s.rows[i] = make([]uint64, counters/16)
+	}
+
+	return s
+}
+
+// index - returns the word and the bit offset of the counter for a hash in a row
+func (s *sketch) index(hash uint64, row int) (int, uint) {
+	// Double hashing: h1 + row*h2 gives independent-enough positions from one 64-bit hash
+	h := (hash + uint64(row)*((hash>>32)|1)) & s.mask
+	return int(h >> 4), uint(h&15) * 4
+}
+
+// Increment - counts one more occurrence of a hash; ages the sketch when the sample is full
+func (s *sketch) Increment(hash uint64) {
+	for row := range s.rows {
+		word, offset := s.index(hash, row)
+		if (s.rows[row][word]>>offset)&counterMax < counterMax {
+			s.rows[row][word] += 1 << offset
+		}
+	}
+
+	s.added++
+	if s.added >= s.samples {
+		s.reset()
+	}
+}
+
+// Estimate - returns the approximate number of occurrences of a hash (0..15)
+func (s *sketch) Estimate(hash uint64) int {
+	estimate := counterMax
+	for row := range s.rows {
+		word, offset := s.index(hash, row)
+		estimate = min(estimate, int((s.rows[row][word]>>offset)&counterMax))
+	}
+	return estimate
+}
+
+// reset - halves every counter, so old popularity fades and new keys get a chance (aging)
+func (s *sketch) reset() {
+	for row := range s.rows {
+		for i := range s.rows[row] {
+			s.rows[row][i] = (s.rows[row][i] >> 1) & resetMask
+		}
+	}
+	s.added /= 2
+}
@@ -0,0 +1,349 @@
+/*
+W-TinyLFU Cache (Window TinyLFU)
+
+What is it?
+W-TinyLFU is the eviction policy of Caffeine (Java) and Ristretto (Go). New items pass through a small LRU window,
+and then an admission filter (TinyLFU) decides whether an item is popular enough to replace something in the main space.
+
+Why is it needed?
+- LRU admits everything: every one-off key pushes out a useful one.
+- LFU keeps exact counters for every key and adapts slowly.
+- TinyLFU estimates the frequency of every key — even keys that are not cached any more —
+  in a few bits per item, and lets in only the keys that were requested more often than the victim.
+
+What's the point?
+- Window (1% of the capacity, LRU) — absorbs bursts: a new key can be hit several times before the filter judges it.
+- Main space (99%, segmented LRU):
+  - probation (20% of the main space) — items that still have to prove their value;
+  - protected (80%) — items hit at least once in probation.
+- Admission: when the window overflows, its oldest item (the candidate) competes with the oldest item
+  of probation (the victim). The one with the higher estimated frequency stays.
+- Frequency is estimated by a Count-Min Sketch with 4-bit counters (see sketch.go).
+- Aging: after 10 * capacity increments every counter is halved, so old popularity fades.
+
+When to use?
+- General-purpose caches where the highest possible hit ratio matters (Zipf-like workloads: web, databases, CDN).
+- When scans and one-off keys must not pollute the cache.
+
+How does it work?
+- Get: the key's frequency is incremented in the sketch (hit or miss).
+  A hit in the window or protected moves the item to the front;
+  a hit in probation promotes the item to protected (the oldest protected item is demoted back to probation).
+- Set of a new key: it goes to the front of the window. If the window is too big, its back item becomes
+  the candidate: it enters probation if the main space has room, otherwise it competes with the victim.
+- All the segments are lru.List from data_struct/LRU, one hash table points to the key in any of them.
+
+### Complexity
+
+| Operation | Time Complexity (O) | Space Complexity (O) |
+|:---|:---:|:---:|
+| Get | O(1) | O(1) |
+| Set (insertion/update) | O(1) | O(1) |
+| Deletion | O(1) | O(1) |
+| Storage | — | O(n) items + O(n) bits of the sketch |
+
+How to know if a problem fits W-TinyLFU?
+- Some keys are much more popular than the others (a skewed distribution).
+- The cache is small compared to the number of distinct keys.
+
+TinyLFU[K, V] implements the common cache.Cache interface (see data_struct/cache) and is safe for concurrent use.
+The constructor follows lru.New: New(capacity, options...).
+
+Examples of using W-TinyLFU:
+See the example.go file.
+*/
+
+package tinylfu
+
+import (
+	"context"
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/LRU"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/stats"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
+)
+
+const (
+	windowRatio    = 0.01 // Share of the capacity for the window
+	protectedRatio = 0.8  // Share of the main space for protected
+)
+
+// segment - the segment an entry belongs to
+type segment int
+
+const (
+	window    segment = iota // Admission window, LRU
+	probation                // Main space, not yet proven
+	protected                // Main space, hit at least once in probation
+)
+
+// entry - a cached key-value pair
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	hash    uint64
+	segment segment
+}
+
+// TinyLFU - a W-TinyLFU cache
+type TinyLFU[K comparable, V any] struct {
+	mu           sync.Mutex
+	capacity     int
+	windowCap    int
+	protectedCap int
+	data         map[K]*lru.Element[*entry[K, V]] // Key -> element of one of the segments
+	segments     [3]*lru.List[*entry[K, V]]       // window, probation, protected; front - most recently used
+	sketch       *sketch                          // Frequency estimates
+	hasher       hasher.Hasher[K]
+	onEvict      func(key K, value V)
+	hits         uint64
+	misses       uint64
+	evictions    uint64 // Evicted or rejected by the admission filter
+}
+
+var _ cache.Cache[string, int] = (*TinyLFU[string, int])(nil)
+
+// Option - configures a W-TinyLFU cache
+type Option[K comparable, V any] func(*TinyLFU[K, V])
+
+// WithOnEvict - sets a callback for entries evicted or rejected because of the capacity
+func WithOnEvict[K comparable, V any](fn func(key K, value V)) Option[K, V] {
+	return func(c *TinyLFU[K, V]) {
+		c.onEvict = fn
+	}
+}
+
+// WithHasher - sets the hash function for the frequency sketch (maphash by default)
+func WithHasher[K comparable, V any](h hasher.Hasher[K]) Option[K, V] {
+	return func(c *TinyLFU[K, V]) {
+		c.hasher = h
+	}
+}
+
+// New - creates a new W-TinyLFU cache with a given capacity (at least 1)
+func New[K comparable, V any](capacity int, opts ...Option[K, V]) *TinyLFU[K, V] {
+	capacity = max(capacity, 1)
+	windowCap := max(int(float64(capacity)*windowRatio), 1)
+	if capacity == 1 {
+		windowCap = 0 // A single slot belongs to the main space
+	}
+
+	c := &TinyLFU[K, V]{
+		capacity:     capacity,
+		windowCap:    windowCap,
+		protectedCap: int(float64(capacity-windowCap) * protectedRatio),
+		data:         make(map[K]*lru.Element[*entry[K, V]], capacity),
+		sketch:       newSketch(capacity),
+		hasher:       hasher.NewMapHash[K](),
+	}
+	for i := range c.segments {
+		c.segments[i] = lru.NewList[*entry[K, V]]()
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Get - returns the value by key and records the access in the sketch
+func (c *TinyLFU[K, V]) Get(ctx context.Context, key K) (V, error) {
+	var zero V
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	hash := c.hasher.Hash(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.sketch.Increment(hash)
+
+	el, ok := c.data[key]
+	if !ok {
+		c.misses++
+		return zero, cache.ErrNotFound
+	}
+
+	c.hits++
+	c.access(el)
+	return el.Value.value, nil
+}
+
+// Peek - returns the value by key without recording the access
+func (c *TinyLFU[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.data[key]; ok {
+		return el.Value.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Set - adds or updates an item; a new item enters the window and may be rejected later by the filter
+func (c *TinyLFU[K, V]) Set(ctx context.Context, key K, value V) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	hash := c.hasher.Hash(key)
+
+	c.mu.Lock()
+
+	if el, ok := c.data[key]; ok {
+		el.Value.value = value
+		c.access(el)
+		c.mu.Unlock()
+		return nil
+	}
+
+	c.sketch.Increment(hash)
+
+	e := &entry[K, V]{key: key, value: value, hash: hash, segment: window}
+	if c.windowCap == 0 {
+		e.segment = probation
+	}
+	c.data[key] = c.segments[e.segment].PushFront(e)
+
+	victim := c.maintain()
+	c.mu.Unlock()
+
+	if victim != nil && c.onEvict != nil {
+		c.onEvict(victim.key, victim.value)
+	}
+	return nil
+}
+
+// Delete - removes an item from the cache
+func (c *TinyLFU[K, V]) Delete(ctx context.Context, key K) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.Remove(key)
+	return nil
+}
+
+// Remove - removes an item, reports whether it was present (OnEvict is not called)
+func (c *TinyLFU[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.data[key]
+	if !ok {
+		return false
+	}
+
+	c.segments[el.Value.segment].Remove(el)
+	delete(c.data, key)
+	return true
+}
+
+// Len - returns the number of items in the cache
+func (c *TinyLFU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.data)
+}
+
+// Frequency - returns the estimated access frequency of a key (0..15), cached or not
+func (c *TinyLFU[K, V]) Frequency(key K) int {
+	hash := c.hasher.Hash(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.sketch.Estimate(hash)
+}
+
+// Stats - returns the hit, miss and eviction counters (rejections included) and the size
+func (c *TinyLFU[K, V]) Stats() stats.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return stats.Stats{Hits: c.hits, Misses: c.misses, Evictions: c.evictions, Size: len(c.data)}
+}
+
+// access - updates the position of a hit item (c.mu must be held)
+func (c *TinyLFU[K, V]) access(el *lru.Element[*entry[K, V]]) {
+	e := el.Value
+
+	switch e.segment {
+	case window, protected:
+		c.segments[e.segment].MoveToFront(el)
+
+	case probation:
+		// Proven: promote to protected, demote the oldest protected item if protected is full
+		c.segments[probation].Remove(el)
+		e.segment = protected
+		c.data[e.key] = c.segments[protected].PushFront(e)
+
+		if c.segments[protected].Len() > c.protectedCap {
+			demoted := c.segments[protected].Remove(c.segments[protected].Back())
+			demoted.segment = probation
+			c.data[demoted.key] = c.segments[probation].PushFront(demoted)
+		}
+	}
+}
+
+// maintain - moves the window overflow to the main space through the admission filter,
+// returns the evicted or rejected entry (c.mu must be held)
+func (c *TinyLFU[K, V]) maintain() *entry[K, V] {
+	if len(c.data) <= c.capacity {
+		// Free space: the window overflow goes to probation without a contest
+		if c.segments[window].Len() > c.windowCap {
+			c.move(c.segments[window].Back(), probation)
+		}
+		return nil
+	}
+
+	// The cache is full: the candidate is the oldest window item (or the new item if there is no window)
+	candidate := c.segments[window].Back()
+	if c.windowCap == 0 || c.segments[window].Len() <= c.windowCap {
+		candidate = c.segments[probation].Front()
+	}
+
+	victim := c.segments[probation].Back()
+	if victim == nil || victim == candidate {
+		victim = c.segments[protected].Back()
+	}
+
+	if victim != nil && victim != candidate && c.admit(candidate.Value, victim.Value) {
+		c.move(candidate, probation)
+		return c.evict(victim)
+	}
+	return c.evict(candidate)
+}
+
+// admit - the TinyLFU filter: the candidate wins only if it is more frequent than the victim (c.mu must be held)
+func (c *TinyLFU[K, V]) admit(candidate, victim *entry[K, V]) bool {
+	return c.sketch.Estimate(candidate.hash) > c.sketch.Estimate(victim.hash)
+}
+
+// move - moves an element to the front of another segment (c.mu must be held)
+func (c *TinyLFU[K, V]) move(el *lru.Element[*entry[K, V]], to segment) {
+	e := el.Value
+	if e.segment == to {
+		c.segments[to].MoveToFront(el)
+		return
+	}
+
+	c.segments[e.segment].Remove(el)
+	e.segment = to
+	c.data[e.key] = c.segments[to].PushFront(e)
+}
+
+// evict - removes an element from the cache (c.mu must be held)
+func (c *TinyLFU[K, V]) evict(el *lru.Element[*entry[K, V]]) *entry[K, V] {
+	e := c.segments[el.Value.segment].Remove(el)
+	delete(c.data, e.key)
+	c.evictions++
+	return e
+}
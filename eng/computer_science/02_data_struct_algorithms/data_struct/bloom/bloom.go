@@ -0,0 +1,373 @@
+/*
+Bloom Filter
+
+What is it?
+A Bloom filter is a probabilistic set: a bit array of m bits and k hash functions. It answers "is this key in the set?"
+with either "definitely not" or "probably yes". It never stores the keys themselves, so a million keys fit
+into about a megabyte at a 1% false-positive rate.
+
+Why is it needed?
+- A cache miss usually goes to a slow backing store (a database, a disk, another service).
+  If most of the requested keys do not exist at all, every such request is wasted work.
+- A filter in front of the lookup answers "definitely not" in memory, so definite misses never reach the store.
+- It is tiny compared to the data it describes and has no false negatives.
+
+What's the point?
+- Add sets k bits of the array, Contains checks that all k bits are set.
+- If at least one bit is 0, the key was never added (no false negatives).
+- If all bits are 1, the key was probably added: the bits may have been set by other keys (a false positive).
+- The false-positive rate depends on the number of bits per key and on k. For n keys and a target rate p:
+  m = -n * ln(p) / ln(2)^2 bits and k = m/n * ln(2) hash functions (OptimalSize).
+
+When to use?
+- In front of caches and hash tables whose misses are expensive (see Guard in guard.go).
+- To skip disk reads for absent keys (LSM trees, databases), to check "already seen" URLs in a crawler.
+- When an occasional false positive is acceptable and a false negative is not.
+- If keys are deleted, a cuckoo filter (data_struct/cuckoo) supports it; at rates below ~0.4%
+  and a nearly full table it is smaller as well (see the benchmarks in cuckoo/cuckoo_test.go).
+
+How does it work?
+- Double hashing: one 64-bit hash h of the key gives h1 = h and h2 = hasher.Mix64(h) | 1, and the i-th bit
+  is (h1 + i*h2) mod m. k hash functions cost a single hash computation (Kirsch and Mitzenmacher).
+- The key hash comes from a hasher.Hasher (data_struct/hasher). The default is xxHash64 with a fixed seed,
+  so a marshaled filter stays valid in another process. With a random seed it would not.
+- Union (bitwise OR) and Intersect (bitwise AND) combine two filters with the same m and k:
+  a union describes both sets, an intersection approximates the common keys.
+- MarshalBinary / UnmarshalBinary save the parameters and the bits, so a filter can be built once and shipped.
+- Counting (counting.go) replaces every bit with a 4-bit counter and supports Delete.
+
+### Complexity
+
+| Operation | Time (O) | Space (O) |
+|:---|:---:|:---:|
+| Add | O(k) | O(1) |
+| Contains | O(k) | O(1) |
+| Union / Intersect | O(m/64) | O(m/64) |
+| Marshal / Unmarshal | O(m/64) | O(m/64) |
+| Memory | — | O(m) bits |
+
+Examples of using Bloom filter:
+See the example.go file.
+*/
+
+package bloom
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
+)
+
+var (
+	// ErrIncompatible - the filters have different sizes or numbers of hash functions
+	ErrIncompatible = errors.New("bloom: filters have different parameters")
+	// ErrFormat - the binary data is not a marshaled filter of this type
+	ErrFormat = errors.New("bloom: invalid binary format")
+)
+
+const (
+	defaultFalsePositiveRate = 0.01
+	formatVersion            = 1
+	headerSize               = 4 + 1 + 4 + 8 + 8 // Magic, version, k, m, count
+
+	// maxK - the largest number of hash functions: 64 already gives a false-positive rate of 2^-64 at the optimal size,
+	// and a larger k read from untrusted bytes would make every Add and Contains loop for billions of iterations
+	maxK = 64
+)
+
+// core - the parameters and the hashing shared by Filter and Counting
+type core[K any] struct {
+	m      uint64 // Number of bits (counters)
+	k      uint32 // Number of hash functions
+	hasher hasher.Hasher[K]
+}
+
+// Option - configures a filter
+type Option[K any] func(*core[K])
+
+// WithHasher - sets the hash function of the keys (xxHash64 with seed 0 by default).
+// Filters are compatible for Union, Intersect and Unmarshal only with the same hasher.
+func WithHasher[K any](h hasher.Hasher[K]) Option[K] {
+	return func(c *core[K]) {
+		if h != nil {
+			c.hasher = h
+		}
+	}
+}
+
+// OptimalSize - returns the number of bits and hash functions for n keys at the false-positive rate p.
+// n < 1 is treated as 1 and p outside (0, 1) as 0.01.
+func OptimalSize(n int, p float64) (m uint64, k int) {
+	if n < 1 {
+		n = 1
+	}
+	if p <= 0 || p >= 1 {
+		p = defaultFalsePositiveRate
+	}
+
+	m = uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k = int(math.Round(float64(m) / float64(n) * math.Ln2))
+	return max(m, 1), max(k, 1)
+}
+
+// newCore - applies the options to the parameters
+func newCore[K any](m uint64, k int, opts []Option[K]) core[K] {
+	c := core[K]{
+		m:      max(m, 1),
+		k:      uint32(min(max(k, 1), maxK)),
+		hasher: hasher.NewXXHash64WithSeed[K](0),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// hashes - returns the two base hashes of a key for double hashing
+func (c *core[K]) hashes(key K) (h1, h2 uint64) {
+	h := c.hasher.Hash(key)
+	return h, hasher.Mix64(h) | 1 // An odd step never cycles over a small subset of a power-of-two m
+}
+
+// location - returns the position of the i-th hash function
+func (c *core[K]) location(h1, h2 uint64, i uint32) uint64 {
+	return (h1 + uint64(i)*h2) % c.m
+}
+
+// compatible - reports whether two filters use the same positions for the same key
+func (c *core[K]) compatible(o *core[K]) bool {
+	return c.m == o.m && c.k == o.k
+}
+
+// Filter - a standard Bloom filter, safe for concurrent use
+type Filter[K any] struct {
+	core[K]
+	words []uint64 // The bit array, 64 bits per word
+	count uint64   // Number of Add calls (an estimate after Union / Intersect)
+	mu    *sync.RWMutex
+}
+
+// New - creates a filter for n expected keys with the target false-positive rate p (e.g. 0.01)
+func New[K any](n int, p float64, opts ...Option[K]) *Filter[K] {
+	m, k := OptimalSize(n, p)
+	return NewWithSize[K](m, k, opts...)
+}
+
+// NewWithSize - creates a filter with m bits and k hash functions (k is clamped to 1..64)
+func NewWithSize[K any](m uint64, k int, opts ...Option[K]) *Filter[K] {
+	c := newCore(m, k, opts)
+	return &Filter[K]{
+		core:  c,
+		words: make([]uint64, (c.m+63)/64),
+		mu:    &sync.RWMutex{},
+	}
+}
+
+// Add - adds a key to the set
+func (f *Filter[K]) Add(key K) {
+	h1, h2 := f.hashes(key)
+
+	f.mu.Lock()
+	for i := range f.k {
+		loc := f.location(h1, h2, i)
+		f.words[loc/64] |= 1 << (loc % 64)
+	}
+	f.count++
+	f.mu.Unlock()
+}
+
+// Contains - reports whether the key may be in the set; false means it was definitely never added
+func (f *Filter[K]) Contains(key K) bool {
+	h1, h2 := f.hashes(key)
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for i := range f.k {
+		loc := f.location(h1, h2, i)
+		if f.words[loc/64]&(1<<(loc%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Cap - returns the number of bits
+func (f *Filter[K]) Cap() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.m
+}
+
+// K - returns the number of hash functions
+func (f *Filter[K]) K() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return int(f.k)
+}
+
+// Count - returns the number of added keys (an estimate after Union or Intersect)
+func (f *Filter[K]) Count() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.count
+}
+
+// FalsePositiveRate - returns the current false-positive probability: (set bits / m)^k
+func (f *Filter[K]) FalsePositiveRate() float64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return math.Pow(float64(f.ones())/float64(f.m), float64(f.k))
+}
+
+// EstimatedCount - estimates the number of distinct keys from the set bits: -m/k * ln(1 - ones/m)
+func (f *Filter[K]) EstimatedCount() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.estimate()
+}
+
+// Reset - removes all keys
+func (f *Filter[K]) Reset() {
+	f.mu.Lock()
+	clear(f.words)
+	f.count = 0
+	f.mu.Unlock()
+}
+
+// Union - adds all keys of another filter (bitwise OR). Both filters must have the same m and k.
+func (f *Filter[K]) Union(other *Filter[K]) error {
+	return f.combine(other, func(a, b uint64) uint64 { return a | b })
+}
+
+// Intersect - keeps only the keys present in both filters (bitwise AND). Both filters must have the same m and k.
+// The result may have more false positives than a filter built from the common keys only.
+func (f *Filter[K]) Intersect(other *Filter[K]) error {
+	return f.combine(other, func(a, b uint64) uint64 { return a & b })
+}
+
+// combine - merges the bits of another filter word by word
+func (f *Filter[K]) combine(other *Filter[K], op func(a, b uint64) uint64) error {
+	if !f.compatible(&other.core) {
+		return ErrIncompatible
+	}
+
+	other.mu.RLock()
+	words := append([]uint64(nil), other.words...)
+	other.mu.RUnlock()
+
+	f.mu.Lock()
+	for i, w := range words {
+		f.words[i] = op(f.words[i], w)
+	}
+	f.count = f.estimate()
+	f.mu.Unlock()
+
+	return nil
+}
+
+// ones - counts the set bits (f.mu must be held)
+func (f *Filter[K]) ones() uint64 {
+	var n int
+	for _, w := range f.words {
+		n += bits.OnesCount64(w)
+	}
+	return uint64(n)
+}
+
+// estimate - estimates the number of keys from the fill ratio (f.mu must be held)
+func (f *Filter[K]) estimate() uint64 {
+	ones := f.ones()
+	if ones == f.m {
+		return f.count // Saturated: the formula gives infinity
+	}
+	m := float64(f.m)
+	return uint64(math.Round(-m / float64(f.k) * math.Log(1-float64(ones)/m)))
+}
+
+// MarshalBinary - encodes the filter: "BLMF", version, k, m, count and the bit array (big-endian)
+func (f *Filter[K]) MarshalBinary() ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	buf := appendHeader(make([]byte, 0, headerSize+8*len(f.words)), "BLMF", f.k, f.m, f.count)
+	for _, w := range f.words {
+		buf = binary.BigEndian.AppendUint64(buf, w)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary - replaces the filter with an encoded one. The hasher is not encoded:
+// the filter must use the same hasher as the one that was marshaled.
+func (f *Filter[K]) UnmarshalBinary(data []byte) error {
+	k, m, count, payload, err := readHeader(data, "BLMF")
+	if err != nil {
+		return err
+	}
+	if !fits(m, len(payload), 64) {
+		return ErrFormat
+	}
+
+	words := make([]uint64, len(payload)/8)
+	for i := range words {
+		words[i] = binary.BigEndian.Uint64(payload[i*8:])
+	}
+
+	if f.mu == nil {
+		f.mu = &sync.RWMutex{} // A zero Filter (e.g. a field being decoded)
+	}
+
+	f.mu.Lock()
+	f.m, f.k = m, k
+	f.words = words
+	f.count = count
+	if f.hasher == nil {
+		f.hasher = hasher.NewXXHash64WithSeed[K](0)
+	}
+	f.mu.Unlock()
+
+	return nil
+}
+
+// appendHeader - encodes the common header of both filter types
+func appendHeader(buf []byte, magic string, k uint32, m, count uint64) []byte {
+	buf = append(buf, magic...)
+	buf = append(buf, formatVersion)
+	buf = binary.BigEndian.AppendUint32(buf, k)
+	buf = binary.BigEndian.AppendUint64(buf, m)
+	return binary.BigEndian.AppendUint64(buf, count)
+}
+
+// fits - reports whether a payload of size bytes holds exactly the words needed for m positions,
+// perWord positions in every 8-byte word
+func fits(m uint64, size, perWord int) bool {
+	if size == 0 || size%8 != 0 {
+		return false
+	}
+	words := uint64(size / 8)
+	return m > (words-1)*uint64(perWord) && m <= words*uint64(perWord)
+}
+
+// readHeader - decodes and validates the common header, returns the rest of the data
+func readHeader(data []byte, magic string) (k uint32, m, count uint64, payload []byte, err error) {
+	if len(data) < headerSize || string(data[:4]) != magic || data[4] != formatVersion {
+		return 0, 0, 0, nil, ErrFormat
+	}
+
+	k = binary.BigEndian.Uint32(data[5:])
+	m = binary.BigEndian.Uint64(data[9:])
+	count = binary.BigEndian.Uint64(data[17:])
+	if k == 0 || k > maxK || m == 0 {
+		return 0, 0, 0, nil, ErrFormat
+	}
+	return k, m, count, data[headerSize:], nil
+}
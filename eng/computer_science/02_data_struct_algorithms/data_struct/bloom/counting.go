@@ -0,0 +1,235 @@
+package bloom
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
+)
+
+const (
+	counterBits    = 4
+	countersInWord = 64 / counterBits
+	counterMax     = 1<<counterBits - 1
+)
+
+// Counting - a counting Bloom filter: every bit is replaced with a 4-bit counter, so keys can be deleted.
+// Add increments the k counters of a key and Delete decrements them. A counter that reached 15 is stuck:
+// it is never decremented, because the number of keys behind it is unknown (this only keeps false positives).
+// It takes 4 times the memory of Filter with the same m.
+type Counting[K any] struct {
+	core[K]
+	words []uint64 // 16 counters per word
+	count uint64   // Number of added minus deleted keys (an estimate after Union / Intersect)
+	mu    *sync.RWMutex
+}
+
+// NewCounting - creates a counting filter for n expected keys with the target false-positive rate p
+func NewCounting[K any](n int, p float64, opts ...Option[K]) *Counting[K] {
+	m, k := OptimalSize(n, p)
+	return NewCountingWithSize[K](m, k, opts...)
+}
+
+// NewCountingWithSize - creates a counting filter with m counters and k hash functions (k is clamped to 1..64)
+func NewCountingWithSize[K any](m uint64, k int, opts ...Option[K]) *Counting[K] {
+	c := newCore(m, k, opts)
+	return &Counting[K]{
+		core:  c,
+		words: make([]uint64, (c.m+countersInWord-1)/countersInWord),
+		mu:    &sync.RWMutex{},
+	}
+}
+
+// Add - adds a key to the set (a key added twice has to be deleted twice)
+func (c *Counting[K]) Add(key K) {
+	h1, h2 := c.hashes(key)
+
+	c.mu.Lock()
+	for i := range c.k {
+		loc := c.location(h1, h2, i)
+		if v := c.get(loc); v < counterMax {
+			c.put(loc, v+1)
+		}
+	}
+	c.count++
+	c.mu.Unlock()
+}
+
+// Contains - reports whether the key may be in the set; false means it is definitely not there
+func (c *Counting[K]) Contains(key K) bool {
+	h1, h2 := c.hashes(key)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.contains(h1, h2)
+}
+
+// Delete - removes a key added before. Returns false (and changes nothing) if the key is definitely not in the set.
+// Deleting a key that was never added, but is a false positive, corrupts the filter: it may create false negatives.
+func (c *Counting[K]) Delete(key K) bool {
+	h1, h2 := c.hashes(key)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.contains(h1, h2) {
+		return false
+	}
+
+	for i := range c.k {
+		loc := c.location(h1, h2, i)
+		if v := c.get(loc); v < counterMax {
+			c.put(loc, v-1)
+		}
+	}
+	if c.count > 0 {
+		c.count--
+	}
+	return true
+}
+
+// Cap - returns the number of counters
+func (c *Counting[K]) Cap() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.m
+}
+
+// K - returns the number of hash functions
+func (c *Counting[K]) K() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return int(c.k)
+}
+
+// Count - returns the number of keys in the set (an estimate after Union or Intersect)
+func (c *Counting[K]) Count() uint64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.count
+}
+
+// Reset - removes all keys
+func (c *Counting[K]) Reset() {
+	c.mu.Lock()
+	clear(c.words)
+	c.count = 0
+	c.mu.Unlock()
+}
+
+// Filter - returns a standard filter with the same keys (a bit is set where the counter is not zero),
+// e.g. to ship a compact copy of the set to other processes
+func (c *Counting[K]) Filter() *Filter[K] {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	f := NewWithSize[K](c.m, int(c.k), WithHasher(c.hasher))
+	for loc := range c.m {
+		if c.get(loc) > 0 {
+			f.words[loc/64] |= 1 << (loc % 64)
+		}
+	}
+	f.count = c.count
+	return f
+}
+
+// Union - adds the counters of another filter (saturating at 15). Both filters must have the same m and k.
+func (c *Counting[K]) Union(other *Counting[K]) error {
+	return c.combine(other, func(a, b uint64) uint64 { return min(a+b, counterMax) }, func(a, b uint64) uint64 { return a + b })
+}
+
+// Intersect - keeps the minimum of every pair of counters. Both filters must have the same m and k.
+func (c *Counting[K]) Intersect(other *Counting[K]) error {
+	lower := func(a, b uint64) uint64 { return min(a, b) }
+	return c.combine(other, lower, lower)
+}
+
+// combine - merges the counters of another filter one by one and the key counts with count
+func (c *Counting[K]) combine(other *Counting[K], op, count func(a, b uint64) uint64) error {
+	if !c.compatible(&other.core) {
+		return ErrIncompatible
+	}
+
+	other.mu.RLock()
+	o := Counting[K]{core: other.core, words: append([]uint64(nil), other.words...), count: other.count}
+	other.mu.RUnlock()
+
+	c.mu.Lock()
+	for loc := range c.m {
+		c.put(loc, op(c.get(loc), o.get(loc)))
+	}
+	c.count = count(c.count, o.count)
+	c.mu.Unlock()
+
+	return nil
+}
+
+// contains - checks the k counters of a key (c.mu must be held)
+func (c *Counting[K]) contains(h1, h2 uint64) bool {
+	for i := range c.k {
+		if c.get(c.location(h1, h2, i)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// get - returns a counter
+func (c *Counting[K]) get(loc uint64) uint64 {
+	shift := loc % countersInWord * counterBits
+	return c.words[loc/countersInWord] >> shift & counterMax
+}
+
+// put - stores a counter
+func (c *Counting[K]) put(loc, v uint64) {
+	shift := loc % countersInWord * counterBits
+	w := &c.words[loc/countersInWord]
+	*w = *w&^(counterMax<<shift) | v<<shift
+}
+
+// MarshalBinary - encodes the filter: "BLMC", version, k, m, count and the counters (big-endian)
+func (c *Counting[K]) MarshalBinary() ([]byte, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	buf := appendHeader(make([]byte, 0, headerSize+8*len(c.words)), "BLMC", c.k, c.m, c.count)
+	for _, w := range c.words {
+		buf = binary.BigEndian.AppendUint64(buf, w)
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary - replaces the filter with an encoded one (the hasher must be the same, see Filter.UnmarshalBinary)
+func (c *Counting[K]) UnmarshalBinary(data []byte) error {
+	k, m, count, payload, err := readHeader(data, "BLMC")
+	if err != nil {
+		return err
+	}
+	if !fits(m, len(payload), countersInWord) {
+		return ErrFormat
+	}
+
+	words := make([]uint64, len(payload)/8)
+	for i := range words {
+		words[i] = binary.BigEndian.Uint64(payload[i*8:])
+	}
+
+	if c.mu == nil {
+		c.mu = &sync.RWMutex{} // A zero Counting (e.g. a field being decoded)
+	}
+
+	c.mu.Lock()
+	c.m, c.k = m, k
+	c.words = words
+	c.count = count
+	if c.hasher == nil {
+		c.hasher = hasher.NewXXHash64WithSeed[K](0)
+	}
+	c.mu.Unlock()
+
+	return nil
+}
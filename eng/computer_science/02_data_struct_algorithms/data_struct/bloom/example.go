@@ -0,0 +1,121 @@
+package bloom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/cache_ttl"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/store"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hash_table"
+)
+
+// Example demonstrates the use of a Bloom filter and a counting Bloom filter
+func Example() {
+	// 1. A filter for 10 000 keys at 1% false positives: about 9.6 bits and 7 hash functions per key
+	const n = 10000
+	f := New[string](n, 0.01)
+	for i := range n {
+		f.Add("user:" + strconv.Itoa(i))
+	}
+	fmt.Printf("m = %d bits (%.1f per key), k = %d\n", f.Cap(), float64(f.Cap())/n, f.K())
+
+	falseNegatives := 0
+	for i := range n {
+		if !f.Contains("user:" + strconv.Itoa(i)) {
+			falseNegatives++
+		}
+	}
+	fmt.Printf("False negatives: %d\n", falseNegatives)
+	fmt.Printf("Measured false-positive rate: %.4f (expected %.4f)\n", measure(f, 100000), f.FalsePositiveRate())
+
+	// 2. Union and Intersect of two filters with the same parameters
+	a := New[string](1000, 0.01)
+	b := New[string](1000, 0.01)
+	for i := range 600 {
+		a.Add("key:" + strconv.Itoa(i))     // 0..599
+		b.Add("key:" + strconv.Itoa(i+400)) // 400..999
+	}
+	union := New[string](1000, 0.01)
+	union.Union(a)
+	union.Union(b)
+	inter := New[string](1000, 0.01)
+	inter.Union(a)
+	inter.Intersect(b)
+	fmt.Printf("Union: ~%d keys, key:100 %t, key:900 %t\n", union.EstimatedCount(), union.Contains("key:100"), union.Contains("key:900"))
+	fmt.Printf("Intersect: ~%d keys, key:500 %t, key:100 %t\n", inter.EstimatedCount(), inter.Contains("key:500"), inter.Contains("key:100"))
+	fmt.Printf("Union with another size: %v\n", union.Union(New[string](10, 0.01)))
+
+	// 3. Marshal the filter and load it elsewhere
+	data, _ := f.MarshalBinary()
+	var loaded Filter[string]
+	if err := loaded.UnmarshalBinary(data); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("Marshaled: %d bytes, loaded filter has user:42 %t, %d keys\n", len(data), loaded.Contains("user:42"), loaded.Count())
+	fmt.Printf("Unmarshal of garbage: %v\n", loaded.UnmarshalBinary([]byte("garbage")))
+
+	// 4. A counting filter supports Delete
+	c := NewCounting[string](1000, 0.01)
+	c.Add("alice")
+	c.Add("bob")
+	fmt.Printf("Counting: alice %t, Delete(alice) %t, alice %t, bob %t, Delete(carol) %t\n",
+		c.Contains("alice"), c.Delete("alice"), c.Contains("alice"), c.Contains("bob"), c.Delete("carol"))
+
+	// 5. Guard in front of a read-through cache: lookups of absent keys never reach the store
+	ctx := context.Background()
+	db := store.NewMemory[string, int]()
+	ttl := cache_ttl.New[string, int](cache_ttl.NoExpiration)
+	defer ttl.Stop()
+
+	guard := NewGuard[string, int](store.NewWriteThrough[string, int](ttl, db), New[string](1000, 0.01))
+	for i := range 100 {
+		guard.Set(ctx, "item:"+strconv.Itoa(i), i)
+	}
+	gets, _ := db.Calls()
+
+	found, missing := 0, 0
+	for i := range 1000 {
+		_, err := guard.Get(ctx, "item:"+strconv.Itoa(i)) // 100 present, 900 absent
+		switch {
+		case err == nil:
+			found++
+		case errors.Is(err, cache.ErrNotFound):
+			missing++
+		}
+	}
+	after, _ := db.Calls()
+	fmt.Printf("Guard: %d found, %d missing, %d answered by the filter, %d store reads\n",
+		found, missing, guard.Skipped(), after-gets)
+
+	// 6. The same idea in front of a hash table lookup
+	table := hash_table.New[string, int]()
+	tableKeys := New[string](100, 0.01)
+	for i := range 100 {
+		table.Put("id:"+strconv.Itoa(i), i)
+		tableKeys.Add("id:" + strconv.Itoa(i))
+	}
+	lookups := 0
+	for i := range 1000 {
+		key := "id:" + strconv.Itoa(i)
+		if tableKeys.Contains(key) {
+			lookups++
+			table.Get(key)
+		}
+	}
+	fmt.Printf("Hash table: %d of 1000 lookups reached the table\n", lookups)
+}
+
+// measure - returns the share of never-added keys the filter reports as present
+func measure(f *Filter[string], probes int) float64 {
+	positives := 0
+	for i := range probes {
+		if f.Contains("absent:" + strconv.Itoa(i)) {
+			positives++
+		}
+	}
+	return float64(positives) / float64(probes)
+}
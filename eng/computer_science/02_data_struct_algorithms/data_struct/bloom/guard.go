@@ -0,0 +1,85 @@
+package bloom
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+)
+
+// Membership - a probabilistic set; both Filter and Counting implement it
+type Membership[K any] interface {
+	Add(key K)
+	Contains(key K) bool
+}
+
+var (
+	_ Membership[string] = (*Filter[string])(nil)
+	_ Membership[string] = (*Counting[string])(nil)
+)
+
+// Guard - a cache decorator that answers definite misses from a Bloom filter.
+// Put it over a cache whose misses are expensive: a read-through cache (loading_cache, store.WriteThrough)
+// or any cache in front of a backing store. A key the filter has never seen returns cache.ErrNotFound
+// without touching the cache, so it never reaches the store.
+//
+// The filter must know every key that exists in the store: fill it with Add when the guard is created
+// (e.g. from a scan of the store); Set adds the new keys itself. Delete does not remove keys from the filter:
+// a stale key only costs one more lookup, while removing a false positive from a Counting filter would create
+// false negatives. Rebuild the filter from time to time if many keys are deleted.
+type Guard[K comparable, V any] struct {
+	cache  cache.Cache[K, V]
+	filter Membership[K]
+
+	skipped atomic.Uint64 // Lookups answered by the filter
+	passed  atomic.Uint64 // Lookups passed to the cache
+}
+
+var _ cache.Cache[string, int] = (*Guard[string, int])(nil)
+
+// NewGuard - wraps a cache with a filter of its keys
+func NewGuard[K comparable, V any](c cache.Cache[K, V], f Membership[K]) *Guard[K, V] {
+	return &Guard[K, V]{cache: c, filter: f}
+}
+
+// Get - returns cache.ErrNotFound at once for a key the filter has never seen, otherwise asks the cache
+func (g *Guard[K, V]) Get(ctx context.Context, key K) (V, error) {
+	var zero V
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	if !g.filter.Contains(key) {
+		g.skipped.Add(1)
+		return zero, cache.ErrNotFound
+	}
+
+	g.passed.Add(1)
+	return g.cache.Get(ctx, key)
+}
+
+// Set - adds the key to the filter and stores the value.
+// The filter goes first, so a concurrent Get never misses a value that is already in the cache.
+func (g *Guard[K, V]) Set(ctx context.Context, key K, value V) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	g.filter.Add(key)
+	return g.cache.Set(ctx, key, value)
+}
+
+// Delete - removes the key from the cache (the filter keeps it, see Guard)
+func (g *Guard[K, V]) Delete(ctx context.Context, key K) error {
+	return g.cache.Delete(ctx, key)
+}
+
+// Skipped - returns the number of lookups answered by the filter without the cache
+func (g *Guard[K, V]) Skipped() uint64 {
+	return g.skipped.Load()
+}
+
+// Passed - returns the number of lookups passed to the cache (hits, false positives and keys deleted later)
+func (g *Guard[K, V]) Passed() uint64 {
+	return g.passed.Load()
+}
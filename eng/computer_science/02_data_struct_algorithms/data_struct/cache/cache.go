@@ -0,0 +1,78 @@
+/*
+Cache (common interface)
+
+What is it?
+A single contract that every cache in this repository implements: the LRU cache (data_struct/LRU),
+the cache with TTL (cache_ttl) and the sharded cache (cache_shard). The code that uses a cache depends
+only on this interface, so the eviction policy can be swapped without touching that code.
+
+Why is it needed?
+- Different caches used to have different APIs: int -> int with -1 on a miss, string -> string with errors,
+  or no errors at all. Switching from one policy to another meant rewriting every call site.
+- With one interface, the policy becomes a construction-time decision.
+
+What's the point?
+- Keys are any comparable type, values are any type (generics).
+- Every operation takes a context.Context: a canceled or expired context is reported as ctx.Err()
+  before the cache is touched, so a cache behind an HTTP handler respects request cancellation.
+- Misses are reported with sentinel errors that are checked with errors.Is, never with magic values.
+- Implementations are configured with functional options (WithTTL, WithShardCapacity, ...)
+  passed to their constructors.
+
+Errors:
+- ErrNotFound — the key is not in the cache (never added, deleted, evicted or expired).
+- ErrTooLarge — the value is bigger than the cost budget of the cache allows for one item (see cost.go).
+
+Cost budgets:
+- lru.LRU and cache_shard.Cache can be bounded by the total cost of the values instead of the number of entries
+  (WithMaxCost / WithShardMaxCost): values of a few bytes and of a few megabytes are not counted as equal.
+- The cost of a value is given by a function (WithCost); DefaultCost uses the length of strings and byte slices.
+
+Lifetimes:
+- The caches with a per-entry lifetime (cache_ttl, cache_shard) read a TTL the same way: a positive TTL expires
+  the entry after that time, a TTL <= 0 (0 or NoExpiration) means the entry never expires.
+  A zero TTL is never "expire immediately": a value that should not be cached is simply not Set.
+
+Statistics:
+- Every cache reports the same counters with Stats (hits, misses, evictions, expirations, size, load latency);
+  cache/stats exposes them in the Prometheus text format.
+
+Implementations:
+- lru.LRU[K, V] — a fixed number of entries, least recently used ones are evicted.
+- lfu.LFUCache[K, V] — a fixed number of entries, least frequently used ones are evicted.
+- arc.ARC[K, V], two_queue.Cache[K, V] and tinylfu.TinyLFU[K, V] — scan-resistant eviction (compare them with cache/trace).
+- cache_ttl.Cache[K, V] — every entry lives for a limited time.
+- loading_cache.LoadingCache[K, V] — a read-through cache over cache_ttl that loads missing values itself.
+- cache_shard.Cache[V] — string keys spread over independently locked shards.
+- peer.Node[V] — cache_shard spread over several processes: every key is loaded by its owner only.
+- store.WriteThrough[K, V] and store.WriteBehind[K, V] — any cache in front of a backing Store, kept up to date on writes.
+- invalidation.Cache[V] — any cache with string keys plus tags, prefix purges and an invalidation bus.
+- bloom.Guard[K, V] (data_struct/bloom) — any cache behind a Bloom filter: definite misses never reach the cache or its store.
+- count_min.Tracked[K, V] (data_struct/count_min) — any cache that reports its hottest keys (approximate top-k).
+*/
+
+package cache
+
+import "context"
+
+// Error - the type of the cache sentinel errors.
+// Being a string type, the errors can be declared as constants and cannot be reassigned.
+type Error string
+
+// Error - returns the error message
+func (e Error) Error() string {
+	return string(e)
+}
+
+// ErrNotFound - the key is not present in the cache
+const ErrNotFound = Error("cache: key not found")
+
+// Cache - the common interface of all caches
+type Cache[K comparable, V any] interface {
+	// Get - returns the value for a key or ErrNotFound
+	Get(ctx context.Context, key K) (V, error)
+	// Set - adds or replaces the value for a key
+	Set(ctx context.Context, key K, value V) error
+	// Delete - removes a key; deleting a missing key is not an error
+	Delete(ctx context.Context, key K) error
+}
@@ -0,0 +1,219 @@
+package cache_server
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// execute - runs one command and writes its reply, reports whether the connection must be closed
+func (s *Server) execute(args []string, w writer) (quit bool) {
+	ctx := context.Background()
+	name := strings.ToUpper(args[0])
+
+	arity, known := commands[name]
+	switch {
+	case !known:
+		w.error(fmt.Sprintf("ERR unknown command '%s'", args[0]))
+		return false
+	case arity > 0 && len(args) != arity, arity < 0 && len(args) < -arity:
+		w.error(fmt.Sprintf("ERR wrong number of arguments for '%s' command", strings.ToLower(name)))
+		return false
+	}
+
+	switch name {
+	case "PING":
+		if len(args) > 1 {
+			w.bulk(args[1])
+		} else {
+			w.simple("PONG")
+		}
+
+	case "ECHO":
+		w.bulk(args[1])
+
+	case "GET":
+		value, err := s.cache.Get(ctx, args[1])
+		if err != nil {
+			w.null()
+		} else {
+			w.bulk(value)
+		}
+
+	case "SET":
+		s.set(ctx, args, w)
+
+	case "DEL":
+		var deleted int64
+		for _, key := range args[1:] {
+			if s.cache.Remove(key) {
+				deleted++
+			}
+		}
+		w.integer(deleted)
+
+	case "EXPIRE":
+		seconds, err := strconv.ParseInt(args[2], 10, 64)
+		if err != nil || seconds > math.MaxInt64/int64(time.Second) {
+			w.error("ERR value is not an integer or out of range")
+			return false
+		}
+
+		// A non-positive lifetime deletes the key, as in Redis
+		if seconds <= 0 {
+			w.integer(boolInt(s.cache.Remove(args[1])))
+			return false
+		}
+		w.integer(boolInt(s.cache.Expire(ctx, args[1], time.Duration(seconds)*time.Second) == nil))
+
+	case "TTL":
+		ttl, err := s.cache.TTL(args[1])
+		switch {
+		case err != nil:
+			w.integer(-2)
+		case ttl < 0:
+			w.integer(-1)
+		default:
+			w.integer(int64((ttl + time.Second/2) / time.Second))
+		}
+
+	case "KEYS":
+		var keys []string
+		s.cache.Range(func(k string, _ string) bool {
+			if match(args[1], k) {
+				keys = append(keys, k)
+			}
+			return true
+		})
+		sort.Strings(keys)
+		w.array(keys)
+
+	case "DBSIZE":
+		w.integer(int64(s.cache.Len()))
+
+	case "INFO":
+		section := ""
+		if len(args) > 1 {
+			section = args[1]
+		}
+		w.bulk(s.info(section))
+
+	case "COMMAND":
+		w.array(nil)
+
+	case "QUIT":
+		w.simple("OK")
+		return true
+	}
+
+	return false
+}
+
+// commands - supported commands and their arity: n - exactly n arguments, -n - at least n (the name included)
+var commands = map[string]int{
+	"PING":    -1,
+	"ECHO":    2,
+	"GET":     2,
+	"SET":     -3,
+	"DEL":     -2,
+	"EXPIRE":  3,
+	"TTL":     2,
+	"KEYS":    2,
+	"DBSIZE":  1,
+	"INFO":    -1,
+	"COMMAND": -1,
+	"QUIT":    1,
+}
+
+// set - SET key value [EX seconds | PX milliseconds]
+func (s *Server) set(ctx context.Context, args []string, w writer) {
+	var ttl time.Duration
+
+	for i := 3; i < len(args); i++ {
+		option := strings.ToUpper(args[i])
+		if (option != "EX" && option != "PX") || i+1 == len(args) || ttl != 0 {
+			w.error("ERR syntax error")
+			return
+		}
+
+		n, err := strconv.ParseInt(args[i+1], 10, 64)
+		unit := time.Second
+		if option == "PX" {
+			unit = time.Millisecond
+		}
+		if err != nil || n <= 0 || n > math.MaxInt64/int64(unit) {
+			w.error("ERR invalid expire time in 'set' command")
+			return
+		}
+
+		ttl = time.Duration(n) * unit
+		i++
+	}
+
+	if err := s.cache.SetWithTTL(ctx, args[1], args[2], ttl); err != nil {
+		// A value over the cost limit of the cache (cache.ErrTooLarge)
+		w.error("ERR " + err.Error())
+		return
+	}
+	w.simple("OK")
+}
+
+// info - the reply of INFO: all sections or only the requested one (server, clients, stats, keyspace)
+func (s *Server) info(section string) string {
+	distribution := s.cache.Distribution()
+	stats := s.cache.Stats()
+
+	sections := []struct {
+		name   string
+		fields []string
+	}{
+		{"Server", []string{
+			"go_version:" + runtime.Version(),
+			"uptime_in_seconds:" + strconv.FormatInt(int64(time.Since(s.started).Seconds()), 10),
+		}},
+		{"Clients", []string{
+			"connected_clients:" + strconv.FormatInt(s.connected.Load(), 10),
+		}},
+		{"Stats", []string{
+			"total_commands_processed:" + strconv.FormatUint(s.processed.Load(), 10),
+			"keyspace_hits:" + strconv.FormatUint(stats.Hits, 10),
+			"keyspace_misses:" + strconv.FormatUint(stats.Misses, 10),
+			"evicted_keys:" + strconv.FormatUint(stats.Evictions, 10),
+			"expired_keys:" + strconv.FormatUint(stats.Expirations, 10),
+		}},
+		{"Keyspace", []string{
+			fmt.Sprintf("db0:keys=%d,shards=%d,imbalance=%.2f",
+				distribution.Total, len(distribution.Shards), distribution.Imbalance),
+		}},
+	}
+
+	var b strings.Builder
+	for _, sec := range sections {
+		if section != "" && !strings.EqualFold(section, sec.name) {
+			continue
+		}
+		if b.Len() > 0 {
+			b.WriteString("\r\n")
+		}
+
+		b.WriteString("# " + sec.name + "\r\n")
+		for _, field := range sec.fields {
+			b.WriteString(field + "\r\n")
+		}
+	}
+
+	return b.String()
+}
+
+// boolInt - 1 for true, 0 for false (RESP has no booleans)
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,127 @@
+package cache_server
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/cache_shard"
+)
+
+// Example demonstrates the cache server on an in-process listener, talking RESP like redis-cli does
+func Example() {
+	server := New(cache_shard.New[string](4))
+
+	// Port 0 - the OS picks a free port
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		fmt.Println("listen:", err)
+		return
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		fmt.Println("dial:", err)
+		return
+	}
+	defer conn.Close()
+
+	client := bufio.NewReader(conn)
+	do := func(args ...string) {
+		conn.Write(encodeCommand(args))
+		fmt.Printf("%-34s -> %s\n", strings.Join(args, " "), readReply(client))
+	}
+
+	do("PING")
+	do("SET", "user:1", "alice")
+	do("SET", "user:2", "bob", "EX", "60")
+	do("SET", "session:1", "token", "PX", "1500")
+	do("GET", "user:1")
+	do("GET", "user:404")
+	do("TTL", "user:1")
+	do("TTL", "user:2")
+	do("EXPIRE", "user:1", "10")
+	do("TTL", "user:1")
+	do("KEYS", "user:*")
+	do("KEYS", "[su]*:[12]")
+	do("DBSIZE")
+	do("DEL", "user:1", "user:404")
+	do("SET", "user:3", "carol", "EX", "zero")
+	do("INCR", "counter")
+	do("INFO", "keyspace")
+
+	// An inline command, as typed in telnet
+	conn.Write([]byte("ECHO hello\r\n"))
+	fmt.Printf("%-34s -> %s\n", "ECHO hello (inline)", readReply(client))
+
+	// A pipeline: three commands in one write, three replies
+	conn.Write(append(append(encodeCommand([]string{"SET", "a", "1"}),
+		encodeCommand([]string{"SET", "b", "2"})...), encodeCommand([]string{"DBSIZE"})...))
+	fmt.Printf("pipeline: %s, %s, %s\n", readReply(client), readReply(client), readReply(client))
+
+	// Graceful shutdown: the idle connection is closed, Serve returns ErrServerClosed
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	fmt.Println("shutdown:", server.Shutdown(ctx))
+	fmt.Println("serve returned:", <-served)
+
+	_, err = client.ReadByte()
+	fmt.Println("client sees the connection closed:", err != nil)
+}
+
+// encodeCommand - encodes a command as a RESP array of bulk strings
+func encodeCommand(args []string) []byte {
+	var b strings.Builder
+	b.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		b.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+	return []byte(b.String())
+}
+
+// readReply - reads one RESP reply and formats it like redis-cli
+func readReply(r *bufio.Reader) string {
+	line, err := readLine(r)
+	if err != nil || line == "" {
+		return fmt.Sprintf("(read error: %v)", err)
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:]
+	case '-':
+		return "(error) " + line[1:]
+	case ':':
+		return "(integer) " + line[1:]
+	case '$':
+		n, _ := strconv.Atoi(line[1:])
+		if n < 0 {
+			return "(nil)"
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return fmt.Sprintf("(read error: %v)", err)
+		}
+		return strconv.Quote(string(buf[:n]))
+	case '*':
+		n, _ := strconv.Atoi(line[1:])
+		items := make([]string, n)
+		for i := range items {
+			items[i] = readReply(r)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return "(unknown reply) " + line
+	}
+}
@@ -0,0 +1,102 @@
+package cache_server
+
+// match - reports whether s matches a Redis glob pattern:
+//   - * matches any sequence (including '/', unlike path.Match),
+//   - ? matches one character,
+//   - [abc], [a-z] and [^a] match one character from (or not from) a set,
+//   - \x matches x literally.
+//
+// Backtracking is done only for the last '*', so the time is O(len(pattern) * len(s)) in the worst case.
+func match(pattern, s string) bool {
+	p, i := 0, 0
+	starP, starI := -1, 0 // Where to resume after the last '*'
+
+	for i < len(s) {
+		if p < len(pattern) {
+			switch pattern[p] {
+			case '*':
+				starP, starI = p, i
+				p++
+				continue
+
+			case '?':
+				p++
+				i++
+				continue
+
+			case '[':
+				if end, ok := matchClass(pattern, p, s[i]); ok {
+					p = end
+					i++
+					continue
+				}
+
+			case '\\':
+				if p+1 < len(pattern) && pattern[p+1] == s[i] {
+					p += 2
+					i++
+					continue
+				}
+
+			default:
+				if pattern[p] == s[i] {
+					p++
+					i++
+					continue
+				}
+			}
+		}
+
+		// Mismatch: let the last '*' swallow one more character
+		if starP < 0 {
+			return false
+		}
+		starI++
+		p, i = starP+1, starI
+	}
+
+	// The rest of the pattern may only be stars
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// matchClass - matches c against the class that starts at pattern[p] == '[',
+// returns the index after the class and whether c belongs to it
+func matchClass(pattern string, p int, c byte) (int, bool) {
+	p++ // Skip '['
+
+	negate := p < len(pattern) && pattern[p] == '^'
+	if negate {
+		p++
+	}
+
+	found := false
+	for p < len(pattern) && pattern[p] != ']' {
+		lo := pattern[p]
+		if lo == '\\' && p+1 < len(pattern) {
+			p++
+			lo = pattern[p]
+		}
+
+		hi := lo
+		if p+2 < len(pattern) && pattern[p+1] == '-' && pattern[p+2] != ']' {
+			hi = pattern[p+2]
+			p += 2
+		}
+		if lo > hi {
+			lo, hi = hi, lo
+		}
+
+		if lo <= c && c <= hi {
+			found = true
+		}
+		p++
+	}
+
+	if p == len(pattern) {
+		return 0, false // An unterminated class matches nothing
+	}
+	return p + 1, found != negate
+}
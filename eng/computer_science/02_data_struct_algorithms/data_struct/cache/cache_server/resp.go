@@ -0,0 +1,137 @@
+package cache_server
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxBulkLen - the longest accepted bulk string (the limit of Redis as well)
+const maxBulkLen = 512 << 20
+
+// maxArgs - the largest accepted number of command arguments
+const maxArgs = 1 << 20
+
+// initialArgs - the initial capacity of the argument slice: the declared count is not trusted up front
+const initialArgs = 16
+
+// maxLineLen - the longest accepted line: an inline command or a header (the inline limit of Redis as well)
+const maxLineLen = 64 << 10
+
+// errProtocol - the client sent something that is not RESP
+var errProtocol = errors.New("Protocol error")
+
+// readCommand - reads one command: a RESP array of bulk strings (*2\r\n$3\r\nGET\r\n$1\r\nk\r\n)
+// or an inline command (GET k\r\n), as typed in telnet
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if line == "" {
+		return nil, nil
+	}
+
+	if line[0] != '*' {
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n > maxArgs {
+		return nil, errProtocol
+	}
+
+	// The declared sizes only limit what is read, memory grows with the data that actually arrives
+	args := make([]string, 0, min(max(n, 0), initialArgs))
+	for i := 0; i < n; i++ {
+		header, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if header == "" || header[0] != '$' {
+			return nil, errProtocol
+		}
+
+		size, err := strconv.Atoi(header[1:])
+		if err != nil || size < 0 || size > maxBulkLen {
+			return nil, errProtocol
+		}
+
+		var buf bytes.Buffer
+		if _, err := io.CopyN(&buf, r, int64(size)+2); err != nil { // The data and \r\n
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		data := buf.Bytes()
+		if data[size] != '\r' || data[size+1] != '\n' {
+			return nil, errProtocol
+		}
+		args = append(args, string(data[:size]))
+	}
+
+	return args, nil
+}
+
+// readLine - reads a line terminated by \r\n (or \n) without the terminator.
+// A line longer than maxLineLen is a protocol error, so a client cannot make the server buffer without bound.
+func readLine(r *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		chunk, err := r.ReadSlice('\n') // Up to the size of the buffer, then ErrBufferFull
+		if len(line)+len(chunk) > maxLineLen+2 {
+			return "", errProtocol
+		}
+		line = append(line, chunk...)
+
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, bufio.ErrBufferFull) {
+			return "", err
+		}
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(line), "\n"), "\r"), nil
+}
+
+// writer - writes RESP replies
+type writer struct {
+	*bufio.Writer
+}
+
+// simple - a simple string: +OK
+func (w writer) simple(s string) {
+	w.WriteString("+" + s + "\r\n")
+}
+
+// error - an error: -ERR message
+func (w writer) error(msg string) {
+	w.WriteString("-" + msg + "\r\n")
+}
+
+// integer - an integer: :42
+func (w writer) integer(n int64) {
+	w.WriteString(":" + strconv.FormatInt(n, 10) + "\r\n")
+}
+
+// bulk - a binary-safe string: $5\r\nhello
+func (w writer) bulk(s string) {
+	w.WriteString("$" + strconv.Itoa(len(s)) + "\r\n" + s + "\r\n")
+}
+
+// null - the null bulk string: a missing value
+func (w writer) null() {
+	w.WriteString("$-1\r\n")
+}
+
+// array - an array of bulk strings
+func (w writer) array(items []string) {
+	w.WriteString("*" + strconv.Itoa(len(items)) + "\r\n")
+	for _, item := range items {
+		w.bulk(item)
+	}
+}
@@ -0,0 +1,233 @@
+/*
+Cache Server (RESP)
+
+What is it?
+A TCP server that exposes the sharded cache (cache_shard) over the Redis protocol (RESP).
+redis-cli and the existing Redis client libraries can talk to it, so local tests do not need a real Redis.
+
+Why is it needed?
+- A cache inside one process cannot be shared by several services or inspected from the outside.
+- Speaking an existing protocol means no new client library has to be written.
+
+What's the point?
+- RESP is a simple text protocol: a command is an array of bulk strings (*2\r\n$3\r\nGET\r\n$3\r\nkey\r\n),
+  a reply is a simple string (+OK), an error (-ERR ...), an integer (:1), a bulk string ($5\r\nhello) or an array.
+- Inline commands (GET key\r\n) are accepted too, so the server can be tried with telnet.
+
+Commands:
+- PING [message], ECHO message
+- GET key, SET key value [EX seconds | PX milliseconds], DEL key [key ...]
+- EXPIRE key seconds, TTL key (-2 - no key, -1 - no expiration)
+- KEYS pattern (glob: *, ?, [a-z], [^a], \x), DBSIZE, INFO [section]
+- QUIT, COMMAND (an empty reply, enough for redis-cli)
+
+How does it work?
+- Every accepted connection is served by its own goroutine: read a command, execute it on the cache, write the reply.
+- Replies are flushed when the client has no more pipelined commands in the buffer,
+  so a pipeline of N commands costs one write instead of N.
+- Shutdown stops accepting connections, lets every connection finish its current command,
+  and waits for the goroutines (or force-closes the connections when the context expires).
+
+Examples of using the cache server:
+See the example.go file (it runs the server on an in-process listener and talks RESP to it);
+the binary is in cache/cmd/cacheserver.
+*/
+
+package cache_server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/cache_shard"
+)
+
+// ErrServerClosed - returned by Serve after Shutdown
+const ErrServerClosed = cache.Error("cache_server: server closed")
+
+// Server - a RESP server over a sharded cache
+type Server struct {
+	cache   *cache_shard.Cache[string]
+	started time.Time
+
+	mu        sync.Mutex
+	listeners map[net.Listener]struct{}
+	conns     map[net.Conn]struct{}
+	closing   atomic.Bool
+	wg        sync.WaitGroup // Connection goroutines
+
+	connected atomic.Int64  // Currently connected clients
+	processed atomic.Uint64 // Commands executed since the start
+}
+
+// New - creates a server for a cache
+func New(c *cache_shard.Cache[string]) *Server {
+	return &Server{
+		cache:     c,
+		started:   time.Now(),
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[net.Conn]struct{}),
+	}
+}
+
+// ListenAndServe - listens on a TCP address (e.g. ":6380") and serves it
+func (s *Server) ListenAndServe(addr string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(l)
+}
+
+// Serve - accepts connections on a listener until Shutdown; always returns a non-nil error
+func (s *Server) Serve(l net.Listener) error {
+	if !s.track(l) {
+		l.Close()
+		return ErrServerClosed
+	}
+	defer s.untrack(l)
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.closing.Load() {
+				return ErrServerClosed
+			}
+
+			var ne net.Error
+			if errors.As(err, &ne) && ne.Timeout() {
+				time.Sleep(10 * time.Millisecond) // A temporary error (e.g. too many open files)
+				continue
+			}
+			return err
+		}
+
+		if !s.trackConn(conn) {
+			conn.Close()
+			return ErrServerClosed
+		}
+
+		go s.serveConn(conn)
+	}
+}
+
+// Shutdown - stops accepting connections and waits until every connection finishes its current command.
+// If ctx expires first, the remaining connections are closed and ctx.Err() is returned.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.closing.Store(true)
+
+	s.mu.Lock()
+	for l := range s.listeners {
+		l.Close()
+	}
+	// Wake up connections blocked in a read: they see the closing flag and exit
+	for conn := range s.conns {
+		conn.SetReadDeadline(time.Now())
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		s.mu.Lock()
+		for conn := range s.conns {
+			conn.Close()
+		}
+		s.mu.Unlock()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// serveConn - the loop of one connection: read a command, execute it, write the reply
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer s.untrackConn(conn)
+
+	s.connected.Add(1)
+	defer s.connected.Add(-1)
+
+	r := bufio.NewReader(conn)
+	w := writer{bufio.NewWriter(conn)}
+
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			if errors.Is(err, errProtocol) {
+				w.error("ERR " + err.Error())
+				w.Flush()
+			}
+			return // EOF, a closed connection or the shutdown deadline
+		}
+		if len(args) == 0 {
+			continue
+		}
+
+		quit := s.execute(args, w)
+		s.processed.Add(1)
+
+		// Flush only when there are no more pipelined commands
+		if r.Buffered() == 0 || quit {
+			if w.Flush() != nil {
+				return
+			}
+		}
+		if quit || s.closing.Load() {
+			return
+		}
+	}
+}
+
+// track - registers a listener, reports false if the server is shutting down
+func (s *Server) track(l net.Listener) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closing.Load() {
+		return false
+	}
+	s.listeners[l] = struct{}{}
+	return true
+}
+
+// untrack - forgets a listener
+func (s *Server) untrack(l net.Listener) {
+	s.mu.Lock()
+	delete(s.listeners, l)
+	s.mu.Unlock()
+}
+
+// trackConn - registers a connection and its goroutine, reports false if the server is shutting down
+func (s *Server) trackConn(conn net.Conn) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closing.Load() {
+		return false
+	}
+	s.conns[conn] = struct{}{}
+	s.wg.Add(1)
+	return true
+}
+
+// untrackConn - closes and forgets a connection
+func (s *Server) untrackConn(conn net.Conn) {
+	s.mu.Lock()
+	delete(s.conns, conn)
+	s.mu.Unlock()
+
+	conn.Close()
+}
@@ -0,0 +1,238 @@
+package cache_server
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/cache_shard"
+)
+
+// client - a test connection that sends RESP commands and reads the replies
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// startServer - serves a new cache on an in-process listener, returns the server, the result of Serve and a client
+func startServer(t *testing.T) (*Server, <-chan error, *client) {
+	t.Helper()
+
+	server := New(cache_shard.New[string](4))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.Serve(listener)
+	}()
+	t.Cleanup(func() {
+		server.Shutdown(context.Background())
+	})
+
+	return server, served, dial(t, listener.Addr().String())
+}
+
+// dial - connects a client to the server
+func dial(t *testing.T, addr string) *client {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.SetDeadline(time.Now().Add(5 * time.Second)) // A hung server fails the test instead of blocking it
+	t.Cleanup(func() { conn.Close() })
+
+	return &client{t: t, conn: conn, r: bufio.NewReader(conn)}
+}
+
+// do - sends a command and returns its reply formatted like redis-cli
+func (c *client) do(args ...string) string {
+	c.t.Helper()
+
+	if _, err := c.conn.Write(encodeCommand(args)); err != nil {
+		c.t.Fatal(err)
+	}
+	return readReply(c.r)
+}
+
+// expect - sends a command and checks its reply
+func (c *client) expect(want string, args ...string) {
+	c.t.Helper()
+
+	if got := c.do(args...); got != want {
+		c.t.Errorf("%s: got %s, want %s", strings.Join(args, " "), got, want)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	_, _, c := startServer(t)
+
+	c.expect("PONG", "PING")
+	c.expect(`"hi"`, "PING", "hi")
+	c.expect(`"hello"`, "ECHO", "hello")
+
+	c.expect("OK", "SET", "user:1", "alice")
+	c.expect("OK", "SET", "user:2", "bob", "EX", "60")
+	c.expect("OK", "SET", "session:1", "token", "px", "90000")
+	c.expect(`"alice"`, "GET", "user:1")
+	c.expect("(nil)", "GET", "user:404")
+	c.expect("(integer) 3", "DBSIZE")
+
+	c.expect("(integer) -1", "TTL", "user:1")
+	c.expect("(integer) 60", "TTL", "user:2")
+	c.expect("(integer) 90", "TTL", "session:1")
+	c.expect("(integer) -2", "TTL", "user:404")
+
+	c.expect("(integer) 1", "EXPIRE", "user:1", "10")
+	c.expect("(integer) 10", "TTL", "user:1")
+	c.expect("(integer) 0", "EXPIRE", "user:404", "10")
+
+	c.expect(`["user:1", "user:2"]`, "KEYS", "user:*")
+	c.expect(`["session:1", "user:1"]`, "KEYS", "[su]*:1")
+	c.expect("[]", "KEYS", "nothing*")
+
+	c.expect("(integer) 1", "DEL", "user:1", "user:404")
+	c.expect("(nil)", "GET", "user:1")
+	c.expect("(integer) 1", "EXPIRE", "user:2", "0") // A non-positive lifetime deletes the key
+	c.expect("(integer) -2", "TTL", "user:2")
+}
+
+func TestErrors(t *testing.T) {
+	_, _, c := startServer(t)
+
+	c.expect("(error) ERR unknown command 'INCR'", "INCR", "counter")
+	c.expect("(error) ERR wrong number of arguments for 'get' command", "GET")
+	c.expect("(error) ERR syntax error", "SET", "k", "v", "EX")
+	c.expect("(error) ERR syntax error", "SET", "k", "v", "EX", "1", "PX", "1")
+	c.expect("(error) ERR invalid expire time in 'set' command", "SET", "k", "v", "EX", "zero")
+	c.expect("(error) ERR invalid expire time in 'set' command", "SET", "k", "v", "PX", "-5")
+	c.expect("(error) ERR value is not an integer or out of range", "EXPIRE", "k", "soon")
+	c.expect("(nil)", "GET", "k")
+
+	// The connection is still usable after errors
+	c.expect("PONG", "PING")
+}
+
+func TestExpiration(t *testing.T) {
+	_, _, c := startServer(t)
+
+	c.expect("OK", "SET", "session", "token", "PX", "50")
+	c.expect(`"token"`, "GET", "session")
+
+	time.Sleep(100 * time.Millisecond)
+	c.expect("(nil)", "GET", "session")
+	c.expect("(integer) -2", "TTL", "session")
+}
+
+func TestPipeline(t *testing.T) {
+	_, _, c := startServer(t)
+
+	// Three commands in one write, three replies in order
+	var batch []byte
+	for _, args := range [][]string{{"SET", "a", "1"}, {"SET", "b", "2"}, {"GET", "a"}, {"DBSIZE"}} {
+		batch = append(batch, encodeCommand(args)...)
+	}
+	if _, err := c.conn.Write(batch); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"OK", "OK", `"1"`, "(integer) 2"} {
+		if got := readReply(c.r); got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	}
+}
+
+func TestInlineCommand(t *testing.T) {
+	_, _, c := startServer(t)
+
+	if _, err := c.conn.Write([]byte("SET greeting hello\r\nGET greeting\nPING\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"OK", `"hello"`, "PONG"} {
+		if got := readReply(c.r); got != want {
+			t.Errorf("got %s, want %s", got, want)
+		}
+	}
+}
+
+func TestLongLineIsRejected(t *testing.T) {
+	for name, line := range map[string]string{
+		"inline":      "SET k " + strings.Repeat("v", 2*maxLineLen) + "\r\n",
+		"bulk header": "*1\r\n$" + strings.Repeat("1", 2*maxLineLen) + "\r\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, c := startServer(t)
+
+			go c.conn.Write([]byte(line)) // The server stops reading in the middle of the line
+			if got, want := readReply(c.r), "(error) ERR Protocol error"; got != want {
+				t.Errorf("got %s, want %s", got, want)
+			}
+			if _, err := c.r.ReadByte(); err == nil {
+				t.Error("the connection is still open")
+			}
+		})
+	}
+}
+
+func TestDeclaredSizesDoNotAllocate(t *testing.T) {
+	// A header declares the largest bulk string and a million arguments, but only a few bytes follow
+	for name, input := range map[string]string{
+		"bulk": "*1\r\n$" + strconv.Itoa(maxBulkLen) + "\r\nabc",
+		"args": "*" + strconv.Itoa(maxArgs) + "\r\n$1\r\na\r\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			var before, after runtime.MemStats
+			runtime.ReadMemStats(&before)
+			_, err := readCommand(bufio.NewReader(strings.NewReader(input)))
+			runtime.ReadMemStats(&after)
+
+			if !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+				t.Errorf("got %v, want an unexpected EOF", err)
+			}
+			if allocated := after.TotalAlloc - before.TotalAlloc; allocated > 1<<20 {
+				t.Errorf("allocated %d bytes for %d bytes of input", allocated, len(input))
+			}
+		})
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	server, served, c := startServer(t)
+	c.expect("OK", "SET", "k", "v")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// The idle connection is woken up and closed, Serve returns ErrServerClosed
+	if err := server.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+	if err := <-served; !errors.Is(err, ErrServerClosed) {
+		t.Errorf("Serve returned %v, want ErrServerClosed", err)
+	}
+	if _, err := c.r.ReadByte(); err == nil {
+		t.Error("the connection is still open")
+	}
+
+	// A closed server does not serve again
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Serve(listener); !errors.Is(err, ErrServerClosed) {
+		t.Errorf("Serve after Shutdown returned %v, want ErrServerClosed", err)
+	}
+}
@@ -18,92 +18,598 @@ When to use?
 - When the cache is used in a multi-threaded environment.
 - Under high load with frequent read/write operations.
 - When lock contention becomes a bottleneck.
+
+Choosing the hash function:
+- The shard is chosen by the key hash, so a predictable hash lets an attacker send keys that all land
+  in the same shard: one lock becomes hot and the whole point of sharding is lost.
+- By default every cache gets SipHash-2-4 with its own random key (see the hasher package).
+- Any other hasher.Hasher[string] (FNV-1a, xxHash64, ...) can be passed with WithHasher.
+
+Resharding at runtime:
+- The owner of a key is decided by a ShardSelector instead of hash % len(shards) (see selector.go).
+  By default shards are placed on a consistent hash ring (see ring.go); Rendezvous and Jump can be set with WithSelector.
+- AddShard and RemoveShard change the ring and move only the keys whose owner has changed,
+  so the cache can grow or shrink without dropping everything it holds (no cold start).
+- Distribution reports how many keys every shard holds, to check the balance.
+
+Capacity, eviction and TTL:
+- Every shard holds at most WithShardCapacity entries. When a shard is full, its least recently used
+  entry is evicted (the same doubly linked list + hash table scheme as in the LRU package).
+- Instead of (or together with) the entry count, every shard can be bounded by the total cost of its values:
+  WithShardMaxCost sets the budget of one shard, WithCost measures a value (cache.DefaultCost by default:
+  the length of strings and byte slices). Values over WithMaxItemCost are rejected with cache.ErrTooLarge.
+- Every entry can have its own lifetime (SetWithTTL); WithTTL sets the default one for Set.
+  Expired entries are removed lazily: on access or when they reach the back of the LRU list.
+- Expire changes the lifetime of an existing key, TTL returns the remaining one.
+- Stats sums the hits, misses, evictions and expirations of all shards (see data_struct/cache/stats);
+  every shard counts under its own lock, so statistics add no contention.
+- Snapshot and Restore (snapshot.go) save the live entries with their remaining lifetimes and LRU order
+  (see data_struct/cache/snapshot); a snapshot can be restored into a cache with another number of shards.
+
+Cache[V] implements the common cache.Cache[string, V] interface (see data_struct/cache).
+Keys are strings because they are hashed to pick a shard; values can be of any type.
+
+### Complexity
+
+| Operation | Time Complexity (O) |
+|:---|:---:|
+| Get / Set / Delete | O(log V)* |
+| Len | O(S) |
+| Range / Clear | O(n) |
+| AddShard / RemoveShard | O(n) |
+
+*Shard lookup on the default ring; the work inside a shard is O(1). S — number of shards.
+
+Examples of using the sharded cache:
+See the example.go file.
 */
 
-package main
+package cache_shard
 
 import (
+	"context"
+	"errors"
 	"fmt"
-
-	"hash/fnv"
+	"sort"
 	"sync"
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/stats"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
 )
 
-// ICache - interface for the cache
-type ICache interface {
-	Set(k string, v string)
-	Get(k string) (string, bool)
-}
+const defaultVirtualNodes = 100 // Ring points per unit of shard weight
 
-// Shard - a cache segment with its own synchronization
-type Shard struct {
-	data map[string]string
-	mu   *sync.RWMutex
+// NoExpiration - returned by TTL for keys that never expire
+const NoExpiration time.Duration = -1
+
+var (
+	ErrShardNotFound = errors.New("shard not found")
+	ErrLastShard     = errors.New("cannot remove the last shard")
+)
+
+// ICache - interface for the cache: the common cache operations plus TTL and iteration
+type ICache[V any] interface {
+	cache.Cache[string, V]
+	SetWithTTL(ctx context.Context, k string, v V, ttl time.Duration) error
+	Len() int
+	Clear()
+	Range(fn func(k string, v V) bool)
 }
 
 // Cache - a cache structure consisting of several shards
-type Cache struct {
-	shards []*Shard
+type Cache[V any] struct {
+	mu            *sync.RWMutex         // Guards the set of shards; written only while resharding
+	shards        map[int]*Shard[V]     // Shard id -> shard
+	selector      ShardSelector         // Decides which shard owns a key
+	hasher        hasher.Hasher[string] // Hash function used to pick a shard
+	shardCapacity int                   // Max entries per shard, 0 - unlimited
+	shardMaxCost  int64                 // Cost budget per shard, 0 - unlimited
+	maxItemCost   int64                 // Max cost of one value, 0 - unlimited
+	costFn        func(value V) int64   // Measures a value for the cost budget
+	ttl           time.Duration         // Default lifetime for Set, 0 - no expiration
+	nextID        int                   // Id for the next added shard
+	retired       counters              // Statistics of the removed shards
+}
+
+var _ ICache[string] = (*Cache[string])(nil)
+
+// options - settings of a sharded cache
+type options struct {
+	hasher        hasher.Hasher[string]
+	selector      ShardSelector
+	virtualNodes  int
+	shardCapacity int
+	shardMaxCost  int64
+	maxItemCost   int64
+	costFn        any // func(V) int64, checked in New
+	ttl           time.Duration
+}
+
+// Option - configures a sharded cache
+type Option func(*options)
+
+// WithHasher - sets the hash function for keys and ring points
+func WithHasher(h hasher.Hasher[string]) Option {
+	return func(o *options) {
+		o.hasher = h
+	}
+}
+
+// WithSelector - sets the strategy that maps keys to shards (a consistent hash ring by default).
+// The selector must be empty: the cache adds its shards itself.
+func WithSelector(s ShardSelector) Option {
+	return func(o *options) {
+		o.selector = s
+	}
+}
+
+// WithVirtualNodes - sets the number of ring points per unit of shard weight for the default ring
+func WithVirtualNodes(n int) Option {
+	return func(o *options) {
+		o.virtualNodes = n
+	}
+}
+
+// WithShardCapacity - limits the number of entries in every shard (LRU eviction)
+func WithShardCapacity(n int) Option {
+	return func(o *options) {
+		o.shardCapacity = max(n, 0)
+	}
+}
+
+// WithShardMaxCost - bounds the total cost of the values in every shard (LRU eviction).
+// The budget of the whole cache is the number of shards times this value.
+func WithShardMaxCost(n int64) Option {
+	return func(o *options) {
+		o.shardMaxCost = max(n, 0)
+	}
 }
 
-// New - creates a new sharded cache with the specified number of shards
-func New(shardCount int64) *Cache {
-	shards := make([]*Shard, shardCount)
+// WithCost - sets the function that measures a value (cache.DefaultCost by default).
+// Its type must match the value type of the cache, otherwise New panics.
+func WithCost[V any](fn func(value V) int64) Option {
+	return func(o *options) {
+		o.costFn = fn
+	}
+}
+
+// WithMaxItemCost - rejects values that cost more than n with cache.ErrTooLarge
+// (the shard budget by default: a bigger value could never be stored)
+func WithMaxItemCost(n int64) Option {
+	return func(o *options) {
+		o.maxItemCost = max(n, 0)
+	}
+}
 
-	for i := range shards {
-		shards[i] = &Shard{
-			data: make(map[string]string),
-			mu:   &sync.RWMutex{}, // Adding a mutex for each shard
+// WithTTL - sets the default lifetime of entries added with Set
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.ttl = max(ttl, 0)
+	}
+}
+
+// New - creates a new sharded cache with the specified number of shards of weight 1.
+// By default keys are distributed with a randomly keyed SipHash, shards are unbounded
+// and entries never expire.
+func New[V any](shardCount int64, opts ...Option) *Cache[V] {
+	o := options{
+		virtualNodes: defaultVirtualNodes,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.hasher == nil {
+		o.hasher = hasher.Default[string]()
+	}
+	if o.selector == nil {
+		o.selector = NewRing(o.virtualNodes, o.hasher)
+	}
+
+	costFn := cache.DefaultCost[V]
+	if o.costFn != nil {
+		fn, ok := o.costFn.(func(V) int64)
+		if !ok {
+			panic(fmt.Sprintf("cache_shard: WithCost: %T does not match the value type", o.costFn))
 		}
+		costFn = fn
+	}
+	if o.shardMaxCost > 0 && (o.maxItemCost == 0 || o.maxItemCost > o.shardMaxCost) {
+		o.maxItemCost = o.shardMaxCost
 	}
 
-	return &Cache{
-		shards: shards,
+	c := &Cache[V]{
+		mu:            &sync.RWMutex{},
+		shards:        make(map[int]*Shard[V], shardCount),
+		selector:      o.selector,
+		hasher:        o.hasher,
+		shardCapacity: o.shardCapacity,
+		shardMaxCost:  o.shardMaxCost,
+		maxItemCost:   o.maxItemCost,
+		costFn:        costFn,
+		ttl:           o.ttl,
 	}
+
+	for i := int64(0); i < shardCount; i++ {
+		c.addShard(1)
+	}
+
+	return c
 }
 
-// Set - adds a key-value pair to the cache
-func (c *Cache) Set(k string, v string) {
+// Set - adds a key-value pair to the cache with the default lifetime
+func (c *Cache[V]) Set(ctx context.Context, k string, v V) error {
+	return c.SetWithTTL(ctx, k, v, c.ttl)
+}
+
+// SetWithTTL - adds a key-value pair that expires after ttl (0 or NoExpiration - never expires).
+// A value over the item cost limit is rejected with cache.ErrTooLarge, and the old value of the key is dropped.
+func (c *Cache[V]) SetWithTTL(ctx context.Context, k string, v V, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	cost := c.costFn(v)
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	shard := c.getShard(k)
 
 	shard.mu.Lock()
 	defer shard.mu.Unlock()
 
-	shard.data[k] = v
+	if c.maxItemCost > 0 && cost > c.maxItemCost {
+		shard.delete(k)
+		return cache.ErrTooLarge
+	}
+
+	shard.set(k, v, cost, expires)
+	return nil
 }
 
 // Get - returns the value for a key from the cache
-func (c *Cache) Get(k string) (string, bool) {
+func (c *Cache[V]) Get(ctx context.Context, k string) (V, error) {
+	if err := ctx.Err(); err != nil {
+		var zero V
+		return zero, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	shard := c.getShard(k)
+
+	// A write lock: a hit moves the entry to the front of the LRU list
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	if v, ok := shard.get(k, time.Now()); ok {
+		return v, nil
+	}
+
+	var zero V
+	return zero, cache.ErrNotFound
+}
+
+// Delete - removes a key from the cache
+func (c *Cache[V]) Delete(ctx context.Context, k string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	shard := c.getShard(k)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	shard.delete(k)
+	return nil
+}
+
+// Remove - removes a key, reports whether it was present and alive
+func (c *Cache[V]) Remove(k string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	shard := c.getShard(k)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	el, ok := shard.data[k]
+	if !ok {
+		return false
+	}
+
+	alive := !el.Value.(*entry[V]).expired(time.Now())
+	shard.removeElement(el)
+	return alive
+}
+
+// Expire - sets a new lifetime for an existing key (0 - never expires), returns ErrNotFound for a missing key
+func (c *Cache[V]) Expire(ctx context.Context, k string, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	shard := c.getShard(k)
+
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	el, ok := shard.data[k]
+	if !ok || el.Value.(*entry[V]).expired(now) {
+		return cache.ErrNotFound
+	}
+
+	e := el.Value.(*entry[V])
+	e.expires = time.Time{}
+	if ttl > 0 {
+		e.expires = now.Add(ttl)
+	}
+	return nil
+}
+
+// TTL - returns the remaining lifetime of a key (NoExpiration if it never expires)
+func (c *Cache[V]) TTL(k string) (time.Duration, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	shard := c.getShard(k)
 
 	shard.mu.RLock()
 	defer shard.mu.RUnlock()
 
-	if val, ok := shard.data[k]; ok {
-		return val, true
+	now := time.Now()
+	el, ok := shard.data[k]
+	if !ok || el.Value.(*entry[V]).expired(now) {
+		return 0, cache.ErrNotFound
+	}
+
+	e := el.Value.(*entry[V])
+	if e.expires.IsZero() {
+		return NoExpiration, nil
+	}
+	return e.expires.Sub(now), nil
+}
+
+// Len - returns the number of stored entries, including expired ones that were not removed yet
+func (c *Cache[V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	n := 0
+	for _, shard := range c.shards {
+		shard.mu.RLock()
+		n += len(shard.data)
+		shard.mu.RUnlock()
+	}
+
+	return n
+}
+
+// Cost - returns the total cost of the stored values, including expired ones that were not removed yet
+func (c *Cache[V]) Cost() int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var cost int64
+	for _, shard := range c.shards {
+		shard.mu.RLock()
+		cost += shard.cost
+		shard.mu.RUnlock()
+	}
+
+	return cost
+}
+
+// Stats - returns the hits, misses, evictions and expirations summed over the shards, the size and the cost
+func (c *Cache[V]) Stats() stats.Stats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	total := c.retired
+	s := stats.Stats{}
+	for _, shard := range c.shards {
+		shard.mu.RLock()
+		total.add(shard.counters)
+		s.Size += len(shard.data)
+		s.Cost += shard.cost
+		shard.mu.RUnlock()
 	}
 
-	return "", false
+	s.Hits, s.Misses = total.hits, total.misses
+	s.Evictions, s.Expirations = total.evictions, total.expirations
+	return s
 }
 
-// getShard - returns the shard for the specified key using hashing
-func (c *Cache) getShard(key string) *Shard {
-	hasher := fnv.New32()
-	_, _ = hasher.Write([]byte(key))
-	hash := hasher.Sum32()
+// Clear - removes all entries from all shards
+func (c *Cache[V]) Clear() {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
 
-	return c.shards[hash%uint32(len(c.shards))]
+	for _, shard := range c.shards {
+		shard.mu.Lock()
+		shard.clear()
+		shard.mu.Unlock()
+	}
 }
 
-func main() {
-	cache := New(10)
-	cache.Set("salary", "500000")
+// Range - calls fn for every live entry until fn returns false.
+// Shards are visited one by one, each under its read lock, so fn must not modify the cache.
+func (c *Cache[V]) Range(fn func(k string, v V) bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	now := time.Now()
+	for _, shard := range c.shards {
+		shard.mu.RLock()
+		for el := shard.order.Front(); el != nil; el = el.Next() {
+			e := el.Value.(*entry[V])
+			if e.expired(now) {
+				continue
+			}
+			if !fn(e.key, e.value) {
+				shard.mu.RUnlock()
+				return
+			}
+		}
+		shard.mu.RUnlock()
+	}
+}
+
+// AddShard - adds a shard with the given weight and moves to it the keys it now owns.
+// Returns the id of the new shard and the number of moved keys.
+func (c *Cache[V]) AddShard(weight int) (id int, moved int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	shard := c.addShard(weight)
 
-	value, ok := cache.Get("salary")
+	// Only keys that now belong to the new shard are moved, all others stay in place
+	for _, old := range c.shards {
+		if old != shard {
+			moved += c.migrate(old)
+		}
+	}
+
+	return shard.id, moved
+}
+
+// RemoveShard - removes a shard and moves its keys to their new owners.
+// Returns the number of moved keys.
+func (c *Cache[V]) RemoveShard(id int) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	shard, ok := c.shards[id]
 	if !ok {
-		fmt.Println("Value not found")
-		return
+		return 0, ErrShardNotFound
+	}
+	if len(c.shards) == 1 {
+		return 0, ErrLastShard
+	}
+
+	c.selector.Remove(id)
+	delete(c.shards, id)
+
+	shard.mu.RLock()
+	c.retired.add(shard.counters)
+	shard.mu.RUnlock()
+
+	return c.migrate(shard), nil
+}
+
+// ShardLoad - the number of keys held by one shard
+type ShardLoad struct {
+	ID     int
+	Weight int
+	Keys   int
+	Share  float64 // Keys / total keys
+}
+
+// Distribution - a report on how keys are spread over shards
+type Distribution struct {
+	Shards    []ShardLoad // Ordered by shard id
+	Total     int         // Total number of keys
+	Imbalance float64     // Max keys-per-weight divided by the mean (1.0 is a perfect balance)
+}
+
+// Distribution - returns the current key balance across shards
+func (c *Cache[V]) Distribution() Distribution {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var report Distribution
+	totalWeight := 0
+
+	for id, shard := range c.shards {
+		shard.mu.RLock()
+		load := ShardLoad{ID: id, Weight: c.selector.Weight(id), Keys: len(shard.data)}
+		shard.mu.RUnlock()
+
+		report.Shards = append(report.Shards, load)
+		report.Total += load.Keys
+		totalWeight += load.Weight
 	}
 
-	fmt.Println("Value:", value)
+	sort.Slice(report.Shards, func(i, j int) bool {
+		return report.Shards[i].ID < report.Shards[j].ID
+	})
+
+	if report.Total == 0 {
+		return report
+	}
+
+	mean := float64(report.Total) / float64(totalWeight)
+	for i := range report.Shards {
+		load := &report.Shards[i]
+		load.Share = float64(load.Keys) / float64(report.Total)
+		report.Imbalance = max(report.Imbalance, float64(load.Keys)/float64(load.Weight)/mean)
+	}
+
+	return report
+}
+
+// addShard - creates a shard and registers it in the selector (c.mu must be held or the cache not yet shared)
+func (c *Cache[V]) addShard(weight int) *Shard[V] {
+	shard := newShard[V](c.nextID, c.shardCapacity, c.shardMaxCost)
+	c.nextID++
+
+	c.shards[shard.id] = shard
+	c.selector.Add(shard.id, weight)
+
+	return shard
+}
+
+// migrate - moves the keys of a shard that are owned by other shards now (c.mu must be held).
+// Entries are moved from the least to the most recently used, so the new owner keeps their order.
+func (c *Cache[V]) migrate(from *Shard[V]) int {
+	from.mu.Lock()
+	defer from.mu.Unlock()
+
+	now := time.Now()
+	moved := 0
+
+	for el := from.order.Back(); el != nil; {
+		prev := el.Prev()
+		e := el.Value.(*entry[V])
+
+		if to := c.getShard(e.key); to != from {
+			from.removeElement(el)
+
+			if !e.expired(now) {
+				to.mu.Lock()
+				to.set(e.key, e.value, e.cost, e.expires)
+				to.mu.Unlock()
+				moved++
+			}
+		}
+
+		el = prev
+	}
+
+	return moved
+}
+
+// getShard - returns the shard for the specified key using the selector
+func (c *Cache[V]) getShard(key string) *Shard[V] {
+	hash := c.hasher.Hash(key)
+
+	return c.shards[c.selector.Locate(hash)]
 }
@@ -0,0 +1,148 @@
+package cache_shard
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
+)
+
+// SelectorReport - how a shard selector behaves when the shard set changes
+type SelectorReport struct {
+	Name              string
+	Imbalance         float64 // Max shard load / mean load with the initial shards
+	ImbalanceOnAdd    float64 // The same after adding one shard
+	ImbalanceOnRemove float64 // The same after removing a middle shard
+	RemappedOnAdd     float64 // Share of keys that changed owner after adding one shard
+	RemappedOnRemove  float64 // Share of keys that changed owner after removing a middle shard
+	IdealRemapped     float64 // The theoretical minimum for adding: 1 / (shards + 1)
+}
+
+// String - formats the report as one line
+func (r SelectorReport) String() string {
+	return fmt.Sprintf("%-10s imbalance %.3f (%.3f after add, %.3f after remove), "+
+		"remapped on add %5.1f%%, on remove %5.1f%% (ideal add %.1f%%)",
+		r.Name, r.Imbalance, r.ImbalanceOnAdd, r.ImbalanceOnRemove,
+		r.RemappedOnAdd*100, r.RemappedOnRemove*100, r.IdealRemapped*100)
+}
+
+// modulo - the naive hash % n selector, used only as a baseline in comparisons
+type modulo struct {
+	shards  []int
+	weights map[int]int
+}
+
+// Add - appends a shard
+func (m *modulo) Add(shard, weight int) {
+	m.shards = append(m.shards, shard)
+	m.weights[shard] = weight
+}
+
+// Remove - removes a shard, shifting all shards after it
+func (m *modulo) Remove(shard int) {
+	for i, s := range m.shards {
+		if s == shard {
+			m.shards = append(m.shards[:i], m.shards[i+1:]...)
+			break
+		}
+	}
+	delete(m.weights, shard)
+}
+
+// Locate - returns shards[hash % n]
+func (m *modulo) Locate(hash uint64) int {
+	if len(m.shards) == 0 {
+		return -1
+	}
+	return m.shards[hash%uint64(len(m.shards))]
+}
+
+// Weight - returns the weight of a shard
+func (m *modulo) Weight(shard int) int {
+	return m.weights[shard]
+}
+
+// CompareSelectors - measures load imbalance (before and after every change of the shard set)
+// and the share of remapped keys for every selector on keyCount keys and shardCount shards of equal weight
+func CompareSelectors(keyCount, shardCount int) []SelectorReport {
+	h := hasher.NewXXHash64WithSeed[string](0)
+
+	hashes := make([]uint64, keyCount)
+	for i := range hashes {
+		hashes[i] = h.Hash("key:" + strconv.Itoa(i))
+	}
+
+	candidates := []struct {
+		name string
+		make func() ShardSelector
+	}{
+		{"modulo", func() ShardSelector { return &modulo{weights: make(map[int]int)} }},
+		{"ring", func() ShardSelector { return NewRing(defaultVirtualNodes, h) }},
+		{"rendezvous", func() ShardSelector { return NewRendezvous(h) }},
+		{"jump", func() ShardSelector { return NewJump() }},
+	}
+
+	reports := make([]SelectorReport, 0, len(candidates))
+	for _, candidate := range candidates {
+		selector := candidate.make()
+		for shard := 0; shard < shardCount; shard++ {
+			selector.Add(shard, 1)
+		}
+
+		before := locateAll(selector, hashes)
+		report := SelectorReport{
+			Name:          candidate.name,
+			Imbalance:     imbalance(before, shardCount),
+			IdealRemapped: 1 / float64(shardCount+1),
+		}
+
+		selector.Add(shardCount, 1)
+		afterAdd := locateAll(selector, hashes)
+		report.RemappedOnAdd = remapped(before, afterAdd)
+		report.ImbalanceOnAdd = imbalance(afterAdd, shardCount+1)
+
+		selector.Remove(shardCount / 2)
+		afterRemove := locateAll(selector, hashes)
+		report.RemappedOnRemove = remapped(afterAdd, afterRemove)
+		report.ImbalanceOnRemove = imbalance(afterRemove, shardCount)
+
+		reports = append(reports, report)
+	}
+
+	return reports
+}
+
+// locateAll - returns the owner of every key
+func locateAll(selector ShardSelector, hashes []uint64) []int {
+	owners := make([]int, len(hashes))
+	for i, hash := range hashes {
+		owners[i] = selector.Locate(hash)
+	}
+	return owners
+}
+
+// remapped - returns the share of keys whose owner differs
+func remapped(before, after []int) float64 {
+	changed := 0
+	for i := range before {
+		if before[i] != after[i] {
+			changed++
+		}
+	}
+	return float64(changed) / float64(len(before))
+}
+
+// imbalance - returns the max shard load divided by the mean load
+func imbalance(owners []int, shardCount int) float64 {
+	loads := make(map[int]int, shardCount)
+	for _, owner := range owners {
+		loads[owner]++
+	}
+
+	maxLoad := 0
+	for _, load := range loads {
+		maxLoad = max(maxLoad, load)
+	}
+
+	return float64(maxLoad) / (float64(len(owners)) / float64(shardCount))
+}
@@ -0,0 +1,134 @@
+package cache_shard
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
+)
+
+// Example demonstrates the use of a sharded cache
+func Example() {
+	ctx := context.Background()
+
+	salaries := New[string](4)
+	salaries.Set(ctx, "salary", "500000")
+
+	value, err := salaries.Get(ctx, "salary")
+	if err != nil {
+		fmt.Println("Value not found")
+		return
+	}
+
+	fmt.Println("Value:", value)
+
+	// The same cache with a fast xxHash64 for trusted internal keys
+	internal := New[string](4, WithHasher(hasher.NewXXHash64[string]()), WithVirtualNodes(200))
+	for i := 0; i < 10000; i++ {
+		internal.Set(ctx, "key:"+strconv.Itoa(i), strconv.Itoa(i))
+	}
+	printDistribution("4 shards", internal.Distribution())
+
+	// Growing at runtime: only about 1/5 of the keys change owner
+	id, moved := internal.AddShard(1)
+	fmt.Printf("Added shard %d, moved %d keys\n", id, moved)
+	printDistribution("5 shards", internal.Distribution())
+
+	// A shard with weight 2 takes about twice as many keys
+	id, moved = internal.AddShard(2)
+	fmt.Printf("Added shard %d with weight 2, moved %d keys\n", id, moved)
+
+	moved, _ = internal.RemoveShard(0)
+	fmt.Printf("Removed shard 0, moved %d keys\n", moved)
+	printDistribution("after resharding", internal.Distribution())
+
+	value, _ = internal.Get(ctx, "key:42")
+	fmt.Println("key:42 is still there:", value)
+
+	// Any selector can be plugged in
+	jumped := New[string](8, WithSelector(NewJump()))
+	jumped.Set(ctx, "salary", "500000")
+	value, _ = jumped.Get(ctx, "salary")
+	fmt.Println("Value with jump hash:", value)
+
+	// How the strategies behave when the shard set changes
+	for _, report := range CompareSelectors(100_000, 10) {
+		fmt.Println(report)
+	}
+
+	// Bounded shards with LRU eviction and per-key TTL
+	bounded := New[[]byte](2, WithShardCapacity(100), WithTTL(time.Minute))
+	for i := 0; i < 1000; i++ {
+		bounded.Set(ctx, "user:"+strconv.Itoa(i), []byte("data"))
+	}
+	fmt.Printf("Bounded cache holds %d of 1000 keys\n", bounded.Len())
+
+	bounded.SetWithTTL(ctx, "otp:123", []byte("4567"), 50*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	_, err = bounded.Get(ctx, "otp:123")
+	fmt.Printf("otp:123 after its TTL is gone: %t\n", errors.Is(err, cache.ErrNotFound))
+
+	bounded.Delete(ctx, "user:999")
+	count := 0
+	bounded.Range(func(k string, v []byte) bool {
+		count++
+		return true
+	})
+	fmt.Printf("Range visited %d entries\n", count)
+
+	// Statistics summed over the shards; a stats.Registry serves them at /metrics
+	stats := bounded.Stats()
+	fmt.Printf("Bounded cache: %d hits, %d misses, %d evictions, %d expirations\n",
+		stats.Hits, stats.Misses, stats.Evictions, stats.Expirations)
+
+	bounded.Clear()
+	fmt.Printf("After Clear: %d entries\n", bounded.Len())
+
+	// A memory budget instead of an entry count: 4 shards of 64 KB, values from bytes to kilobytes
+	blobs := New[[]byte](4, WithShardMaxCost(64<<10), WithMaxItemCost(16<<10))
+	for i := 0; i < 1000; i++ {
+		blobs.Set(ctx, "blob:"+strconv.Itoa(i), make([]byte, 100+i*10))
+	}
+	fmt.Printf("Budgeted cache: %d entries, %d KB of %d KB\n", blobs.Len(), blobs.Cost()>>10, 4*64)
+
+	err = blobs.Set(ctx, "blob:huge", make([]byte, 32<<10))
+	fmt.Printf("32 KB blob over the 16 KB item limit: %v\n", err)
+
+	// A custom cost: a struct value measured by its payload
+	type page struct{ body string }
+	pages := New[page](2, WithShardMaxCost(1000), WithCost(func(p page) int64 { return int64(len(p.body)) }))
+	pages.Set(ctx, "/", page{body: "<html>home</html>"})
+	fmt.Printf("pages cost: %d\n", pages.Cost())
+
+	// Used through the common interface
+	var profiles cache.Cache[string, int] = New[int](4)
+	profiles.Set(ctx, "age:alice", 30)
+	age, _ := profiles.Get(ctx, "age:alice")
+	fmt.Printf("alice is %d\n", age)
+
+	// Snapshot and restore into a cache with another number of shards
+	source := New[int](4)
+	for i := 0; i < 100; i++ {
+		source.Set(ctx, "user:"+strconv.Itoa(i), i)
+	}
+
+	var snap bytes.Buffer
+	source.Snapshot(&snap)
+
+	resized := New[int](7)
+	err = resized.Restore(&snap)
+	fmt.Printf("restored %d entries into 7 shards, err: %v\n", resized.Len(), err)
+}
+
+// printDistribution - prints a distribution report
+func printDistribution(title string, report Distribution) {
+	fmt.Printf("%s: %d keys, imbalance %.2f\n", title, report.Total, report.Imbalance)
+	for _, load := range report.Shards {
+		fmt.Printf("  shard %d (weight %d): %d keys (%.1f%%)\n", load.ID, load.Weight, load.Keys, load.Share*100)
+	}
+}
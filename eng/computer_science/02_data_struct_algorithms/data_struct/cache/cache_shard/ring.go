@@ -0,0 +1,108 @@
+/*
+Consistent Hashing Ring
+
+What is it?
+The hash space [0, 2^64) is treated as a circle. Every shard is placed on the circle at several points
+(virtual nodes), and a key belongs to the first shard point met when walking clockwise from the key hash.
+
+Why not hash % shards?
+- With modulo, changing the number of shards from N to N+1 moves almost every key (about N/(N+1) of them).
+- On a ring, a new shard only takes over the arcs in front of its own points: about 1/(N+1) of the keys move.
+- Removing a shard moves only the keys of that shard to its neighbours.
+
+Virtual nodes and weights:
+- One point per shard gives very uneven arcs. Many points per shard (virtual nodes) even out the load.
+- A shard with weight w gets w times more points, so it receives w times more keys (e.g. a bigger machine).
+
+### Complexity
+
+| Operation | Time Complexity (O) |
+|:---|:---:|
+| Locate key | O(log(V)) |
+| Add / remove shard | O(V log(V)) |
+
+*V — total number of virtual nodes on the ring.
+*/
+
+package cache_shard
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
+)
+
+// ringPoint - a virtual node on the ring
+type ringPoint struct {
+	hash  uint64
+	shard int
+}
+
+// Ring - a consistent hash ring with weighted virtual nodes
+type Ring struct {
+	hasher       hasher.Hasher[string] // Places virtual nodes on the ring
+	virtualNodes int                   // Points per unit of weight
+	points       []ringPoint           // Sorted by hash
+	weights      map[int]int           // Shard id -> weight
+}
+
+// NewRing - creates an empty ring
+func NewRing(virtualNodes int, h hasher.Hasher[string]) *Ring {
+	return &Ring{
+		hasher:       h,
+		virtualNodes: max(virtualNodes, 1),
+		weights:      make(map[int]int),
+	}
+}
+
+// Add - places a shard on the ring with the given weight
+func (r *Ring) Add(shard, weight int) {
+	if _, ok := r.weights[shard]; ok {
+		r.Remove(shard)
+	}
+
+	weight = max(weight, 1)
+	r.weights[shard] = weight
+
+	for i := 0; i < r.virtualNodes*weight; i++ {
+		label := strconv.Itoa(shard) + "#" + strconv.Itoa(i)
+		r.points = append(r.points, ringPoint{hash: r.hasher.Hash(label), shard: shard})
+	}
+
+	sort.Slice(r.points, func(i, j int) bool {
+		return r.points[i].hash < r.points[j].hash
+	})
+}
+
+// Remove - takes all points of a shard off the ring
+func (r *Ring) Remove(shard int) {
+	delete(r.weights, shard)
+
+	r.points = slices.DeleteFunc(r.points, func(p ringPoint) bool {
+		return p.shard == shard
+	})
+}
+
+// Locate - returns the shard that owns the given key hash, or -1 if the ring is empty
+func (r *Ring) Locate(hash uint64) int {
+	if len(r.points) == 0 {
+		return -1
+	}
+
+	// The first point clockwise from the key; past the last point we wrap around to the first one
+	i := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i].hash >= hash
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+
+	return r.points[i].shard
+}
+
+// Weight - returns the weight of a shard (0 if it is not on the ring)
+func (r *Ring) Weight(shard int) int {
+	return r.weights[shard]
+}
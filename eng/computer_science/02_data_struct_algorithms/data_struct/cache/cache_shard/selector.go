@@ -0,0 +1,169 @@
+/*
+Shard Selectors
+
+What is it?
+A shard selector decides which shard owns a key. The cache only gives it the key hash and tells it
+when shards are added or removed, so the strategy can be swapped without touching the cache.
+
+Available strategies:
+- Ring (ring.go) — consistent hashing with virtual nodes: O(log V) lookup, memory for V points.
+- Rendezvous — highest random weight (HRW) hashing: every shard "bids" for the key with hash(key, shard),
+  the highest bid wins. No extra memory and a perfect balance on average, but a lookup is O(N).
+  When a shard leaves, only its keys move; when a shard joins, it takes only the keys it now wins.
+- Jump — Google's jump consistent hash (Lamping & Veach, 2014): O(ln N) time, no memory, almost perfect balance.
+  It only knows buckets 0..n-1, so it can cheaply add or remove the LAST bucket. Removing a shard
+  from the middle is done by moving the last bucket into its place, which remaps about twice as many keys.
+
+### Complexity
+
+| Selector | Locate | Add / Remove | Memory |
+|:---|:---:|:---:|:---:|
+| Ring | O(log V) | O(V log V) | O(V) |
+| Rendezvous | O(N) | O(N) | O(N) |
+| Jump | O(ln N) | O(w) | O(N) |
+
+*V — number of virtual nodes, N — number of shards, w — weight of the shard.
+*/
+
+package cache_shard
+
+import (
+	"math"
+	"slices"
+	"strconv"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
+)
+
+// ShardSelector - decides which shard owns a key hash
+type ShardSelector interface {
+	Add(shard, weight int)  // Adds a shard (or changes its weight)
+	Remove(shard int)       // Removes a shard
+	Locate(hash uint64) int // Returns the owner of a key hash, or -1 if there are no shards
+	Weight(shard int) int   // Returns the weight of a shard, 0 if it is unknown
+}
+
+// Rendezvous - highest random weight (HRW) hashing
+type Rendezvous struct {
+	hasher  hasher.Hasher[string] // Gives every shard its own seed
+	shards  []int
+	seeds   map[int]uint64
+	weights map[int]int
+}
+
+// NewRendezvous - creates an empty HRW selector
+func NewRendezvous(h hasher.Hasher[string]) *Rendezvous {
+	return &Rendezvous{
+		hasher:  h,
+		seeds:   make(map[int]uint64),
+		weights: make(map[int]int),
+	}
+}
+
+// Add - adds a shard with the given weight
+func (r *Rendezvous) Add(shard, weight int) {
+	if _, ok := r.weights[shard]; !ok {
+		r.shards = append(r.shards, shard)
+	}
+
+	r.weights[shard] = max(weight, 1)
+	r.seeds[shard] = r.hasher.Hash(strconv.Itoa(shard))
+}
+
+// Remove - removes a shard
+func (r *Rendezvous) Remove(shard int) {
+	r.shards = slices.DeleteFunc(r.shards, func(s int) bool { return s == shard })
+	delete(r.weights, shard)
+	delete(r.seeds, shard)
+}
+
+// Locate - returns the shard with the highest score for the key hash
+func (r *Rendezvous) Locate(hash uint64) int {
+	best, bestScore := -1, math.Inf(-1)
+
+	for _, shard := range r.shards {
+		// Weighted HRW: score = -w / ln(u), where u in (0, 1) is the key/shard hash.
+		// For equal weights this keeps the order of the plain hashes.
+		u := (float64(hasher.Mix64(hash^r.seeds[shard])>>11) + 0.5) / (1 << 53)
+		score := -float64(r.weights[shard]) / math.Log(u)
+
+		if score > bestScore {
+			best, bestScore = shard, score
+		}
+	}
+
+	return best
+}
+
+// Weight - returns the weight of a shard
+func (r *Rendezvous) Weight(shard int) int {
+	return r.weights[shard]
+}
+
+// Jump - jump consistent hash over a table of buckets
+type Jump struct {
+	buckets []int       // Bucket -> shard id; a shard of weight w owns w buckets
+	weights map[int]int // Shard id -> weight
+}
+
+// NewJump - creates an empty jump hash selector
+func NewJump() *Jump {
+	return &Jump{weights: make(map[int]int)}
+}
+
+// Add - appends buckets for a shard at the end of the table
+func (j *Jump) Add(shard, weight int) {
+	if _, ok := j.weights[shard]; ok {
+		j.Remove(shard)
+	}
+
+	weight = max(weight, 1)
+	j.weights[shard] = weight
+
+	for i := 0; i < weight; i++ {
+		j.buckets = append(j.buckets, shard)
+	}
+}
+
+// Remove - removes the buckets of a shard, filling each hole with the last bucket
+func (j *Jump) Remove(shard int) {
+	delete(j.weights, shard)
+
+	for i := len(j.buckets) - 1; i >= 0; i-- {
+		if j.buckets[i] != shard {
+			continue
+		}
+
+		last := len(j.buckets) - 1
+		j.buckets[i] = j.buckets[last]
+		j.buckets = j.buckets[:last]
+	}
+}
+
+// Locate - returns the shard of the bucket chosen by jump hash
+func (j *Jump) Locate(hash uint64) int {
+	if len(j.buckets) == 0 {
+		return -1
+	}
+
+	return j.buckets[jumpHash(hash, len(j.buckets))]
+}
+
+// Weight - returns the weight of a shard
+func (j *Jump) Weight(shard int) int {
+	return j.weights[shard]
+}
+
+// jumpHash - maps a key to a bucket in [0, buckets).
+// The key "jumps" forward through bucket numbers; the last jump below the bucket count wins.
+func jumpHash(key uint64, buckets int) int {
+	b, next := int64(-1), int64(0)
+
+	for next < int64(buckets) {
+		b = next
+		key = key*2862933555777941757 + 1
+		next = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+
+	return int(b)
+}
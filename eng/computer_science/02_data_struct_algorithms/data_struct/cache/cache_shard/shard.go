@@ -0,0 +1,138 @@
+package cache_shard
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// entry - a value stored in a shard
+type entry[V any] struct {
+	key     string
+	value   V
+	cost    int64
+	expires time.Time // Zero time means the entry never expires
+}
+
+// expired - reports whether the entry's lifetime is over
+func (e *entry[V]) expired(now time.Time) bool {
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+// Shard - a cache segment with its own synchronization, capacity and LRU order
+type Shard[V any] struct {
+	id       int
+	data     map[string]*list.Element // Key -> element of the recency list
+	order    *list.List               // Front - most recently used, back - least recently used
+	capacity int                      // Max entries, 0 - unlimited
+	maxCost  int64                    // Cost budget, 0 - unlimited
+	cost     int64                    // Total cost of the stored values
+	counters counters
+	mu       *sync.RWMutex
+}
+
+// counters - the statistics of a shard (guarded by the shard mutex)
+type counters struct {
+	hits, misses, evictions, expirations uint64
+}
+
+// add - sums the counters of two shards
+func (c *counters) add(o counters) {
+	c.hits += o.hits
+	c.misses += o.misses
+	c.evictions += o.evictions
+	c.expirations += o.expirations
+}
+
+// newShard - creates an empty shard
+func newShard[V any](id, capacity int, maxCost int64) *Shard[V] {
+	return &Shard[V]{
+		id:       id,
+		data:     make(map[string]*list.Element),
+		order:    list.New(),
+		capacity: capacity,
+		maxCost:  maxCost,
+		mu:       &sync.RWMutex{}, // Adding a mutex for each shard
+	}
+}
+
+// get - returns a live value and marks it as recently used (s.mu must be held for writing)
+func (s *Shard[V]) get(key string, now time.Time) (V, bool) {
+	var zero V
+
+	el, ok := s.data[key]
+	if !ok {
+		s.counters.misses++
+		return zero, false
+	}
+
+	e := el.Value.(*entry[V])
+	if e.expired(now) {
+		s.removeElement(el) // Expired entries are removed lazily on access
+		s.counters.misses++
+		s.counters.expirations++
+		return zero, false
+	}
+
+	s.counters.hits++
+	s.order.MoveToFront(el)
+	return e.value, true
+}
+
+// set - stores an entry, evicting the least recently used ones if the shard is full
+// or over its cost budget (s.mu must be held)
+func (s *Shard[V]) set(key string, value V, cost int64, expires time.Time) {
+	if el, ok := s.data[key]; ok {
+		e := el.Value.(*entry[V])
+		s.cost += cost - e.cost
+		e.value = value
+		e.cost = cost
+		e.expires = expires
+		s.order.MoveToFront(el)
+	} else {
+		s.data[key] = s.order.PushFront(&entry[V]{key: key, value: value, cost: cost, expires: expires})
+		s.cost += cost
+	}
+
+	s.evict()
+}
+
+// delete - removes an entry (s.mu must be held)
+func (s *Shard[V]) delete(key string) bool {
+	el, ok := s.data[key]
+	if !ok {
+		return false
+	}
+
+	s.removeElement(el)
+	return true
+}
+
+// evict - drops entries from the back of the list while the shard is over capacity or over its cost budget
+func (s *Shard[V]) evict() {
+	for s.capacity > 0 && len(s.data) > s.capacity || s.maxCost > 0 && s.cost > s.maxCost {
+		back := s.order.Back()
+
+		// An expired entry that reached the back is counted as an expiration, not as an eviction
+		if back.Value.(*entry[V]).expired(time.Now()) {
+			s.counters.expirations++
+		} else {
+			s.counters.evictions++
+		}
+		s.removeElement(back)
+	}
+}
+
+// removeElement - removes an element from both the list and the map and releases its cost
+func (s *Shard[V]) removeElement(el *list.Element) {
+	e := s.order.Remove(el).(*entry[V])
+	delete(s.data, e.key)
+	s.cost -= e.cost
+}
+
+// clear - drops all entries (s.mu must be held)
+func (s *Shard[V]) clear() {
+	s.data = make(map[string]*list.Element)
+	s.order.Init()
+	s.cost = 0
+}
@@ -0,0 +1,75 @@
+package cache_shard
+
+import (
+	"io"
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/snapshot"
+)
+
+// Snapshot - writes the live entries of every shard from the least to the most recently used
+// (see data_struct/cache/snapshot). Shards are read one by one, so the snapshot is consistent per shard.
+func (c *Cache[V]) Snapshot(w io.Writer) error {
+	now := time.Now()
+
+	c.mu.RLock()
+	var records []snapshot.Record[string, V]
+	for _, shard := range c.shards {
+		shard.mu.RLock()
+		for el := shard.order.Back(); el != nil; el = el.Prev() {
+			e := el.Value.(*entry[V])
+			if e.expired(now) {
+				continue
+			}
+
+			rec := snapshot.Record[string, V]{Key: e.key, Value: e.value}
+			if !e.expires.IsZero() {
+				rec.Remaining = max(e.expires.Sub(now), time.Nanosecond)
+			}
+			records = append(records, rec)
+		}
+		shard.mu.RUnlock()
+	}
+	c.mu.RUnlock()
+
+	return snapshot.Write(w, snapshot.KindShard, records)
+}
+
+// Restore - replaces the content of the cache with a snapshot.
+// Keys are placed by the current selector, so a snapshot can be restored into a cache with another number of shards.
+func (c *Cache[V]) Restore(r io.Reader) error {
+	records, err := snapshot.Read[string, V](r, snapshot.KindShard)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, shard := range c.shards {
+		shard.mu.Lock()
+		shard.clear()
+		shard.mu.Unlock()
+	}
+
+	// Records of every shard go from the oldest to the newest, so set rebuilds the LRU order
+	for _, rec := range records {
+		var expires time.Time
+		if rec.Remaining > 0 {
+			expires = now.Add(rec.Remaining)
+		}
+
+		cost := c.costFn(rec.Value)
+		if c.maxItemCost > 0 && cost > c.maxItemCost {
+			continue
+		}
+
+		shard := c.getShard(rec.Key)
+		shard.mu.Lock()
+		shard.set(rec.Key, rec.Value, cost, expires)
+		shard.mu.Unlock()
+	}
+	return nil
+}
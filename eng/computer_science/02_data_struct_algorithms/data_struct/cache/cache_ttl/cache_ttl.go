@@ -18,39 +18,152 @@ When to use?
 - When data in the cache can become obsolete.
 - To limit memory usage.
 - When it's important to have up-to-date data.
+
+Cache[K, V] is generic and implements the common cache.Cache interface (see data_struct/cache).
+
+Expiry index:
+- Expired elements are removed in two ways: lazily by Get, and by a background janitor every sweep interval
+  (10 seconds by default, WithSweepInterval).
+- Scanning the whole map under the lock would stall all readers when there are millions of keys,
+  so the janitor asks an expiry index for the keys that are already expired and touches only them.
+- The index is selectable (WithIndex):
+  - HeapIndex (default) — a min-heap by expiration time (heap.Heap from data_struct/heap).
+    The root is the earliest expiration; the janitor pops while the root is expired.
+  - WheelIndex — a hierarchical timing wheel with one tick per sweep interval (see wheel.go).
+    Cheaper Set, but expiration is rounded up to the next tick (Get still checks the exact time).
+
+### Complexity
+
+| Operation | Heap index | Timing wheel |
+|:---|:---:|:---:|
+| Get | O(1) | O(1) |
+| Set / Delete | O(log n) | O(1) |
+| Cleanup | O(expired * log n) | O(expired)* |
+
+*Plus the amortized cost of cascading keys from the upper levels of the wheel.
+
+Per-key lifetime:
+- Set uses the default TTL of the cache (the argument of New); SetWithTTL gives a key its own lifetime
+  (e.g. a short-lived token and a long-lived session in one cache).
+- NoExpiration (or any TTL <= 0, see data_struct/cache) means the element never expires
+  (and is never put into the expiry index).
+- TTL(key) returns the remaining lifetime of a key.
+- Sliding expiration (WithSlidingExpiration): every Get restarts the lifetime of the element,
+  so an active session stays alive and an idle one expires.
+- OnExpire registers a hook that the janitor goroutine calls for every expired element (outside the lock).
+  While a hook is registered, Get does not remove expired elements itself: they are left to the janitor,
+  so the hook sees every expiration exactly once.
+
+Statistics:
+- Stats counts hits and misses of Get and expirations (lazy and by the janitor) with atomics,
+  so the read path stays under the read lock (see data_struct/cache/stats).
+
+Persistence:
+- Snapshot and Restore (snapshot.go) save the live elements with their remaining lifetimes
+  and load them back after a restart (see data_struct/cache/snapshot).
+
+Examples of using Cache with TTL:
+See the example.go file.
 */
 
 package cache_ttl
 
 import (
 	"context"
-	"errors"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/stats"
 )
 
-var ErrNotFound = errors.New("key not found")
+// ErrNotFound - the key is missing or expired (the same error as cache.ErrNotFound)
+const ErrNotFound = cache.ErrNotFound
+
+// NoExpiration - a TTL for elements that never expire
+const NoExpiration time.Duration = -1
 
 // elem - a cache element with data and a lifetime
-type elem struct {
-	value    string
-	exp_date time.Time
+type elem[V any] struct {
+	value    V
+	exp_date time.Time     // Zero time means the element never expires
+	ttl      time.Duration // The lifetime the element was set with (for sliding expiration)
+}
+
+// expired - reports whether the element's lifetime is over
+func (e elem[V]) expired(now time.Time) bool {
+	return !e.exp_date.IsZero() && e.exp_date.Before(now)
 }
 
+const defaultSweepInterval = 10 * time.Second
+
 // Cache - a cache structure with TTL
-type Cache struct {
-	storage map[string]elem
-	mu      *sync.RWMutex
-	TTL     time.Duration // Elements' lifetime
-	done    chan struct{} // Channel to stop the cleanup goroutine
+type Cache[K comparable, V any] struct {
+	storage  map[K]elem[V]
+	index    expiryIndex[K] // Expiration times for the janitor
+	mu       *sync.RWMutex
+	ttl      time.Duration // Default lifetime of the elements
+	sweep    time.Duration // Interval of the background cleanup
+	sliding  bool          // Get restarts the lifetime of an element
+	onExpire func(key K, value V)
+	done     chan struct{} // Channel to stop the cleanup goroutine
+
+	hits, misses, expirations atomic.Uint64
+}
+
+var _ cache.Cache[string, string] = (*Cache[string, string])(nil)
+
+// options - settings of a cache with TTL
+type options struct {
+	sweep   time.Duration
+	index   IndexKind
+	sliding bool
+}
+
+// Option - configures a cache with TTL
+type Option func(*options)
+
+// WithSweepInterval - sets how often the janitor removes expired elements (10 seconds by default)
+func WithSweepInterval(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.sweep = d
+		}
+	}
+}
+
+// WithIndex - selects the expiry index: HeapIndex (default) or WheelIndex
+func WithIndex(kind IndexKind) Option {
+	return func(o *options) {
+		o.index = kind
+	}
+}
+
+// WithSlidingExpiration - makes every Get restart the lifetime of the element
+func WithSlidingExpiration() Option {
+	return func(o *options) {
+		o.sliding = true
+	}
 }
 
-// New - creates a new cache with the specified TTL
-func New(ttl time.Duration) *Cache {
-	cache := &Cache{
-		storage: make(map[string]elem),
+// New - creates a new cache with the specified TTL (NoExpiration or 0 - elements live until deleted)
+func New[K comparable, V any](ttl time.Duration, opts ...Option) *Cache[K, V] {
+	o := options{
+		sweep: defaultSweepInterval,
+		index: HeapIndex,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	cache := &Cache[K, V]{
+		storage: make(map[K]elem[V]),
+		index:   newIndex[K](o.index, time.Now(), o.sweep),
 		mu:      &sync.RWMutex{},
-		TTL:     ttl,
+		ttl:     ttl,
+		sweep:   o.sweep,
+		sliding: o.sliding,
 		done:    make(chan struct{}),
 	}
 
@@ -59,57 +172,126 @@ func New(ttl time.Duration) *Cache {
 	return cache
 }
 
-// Set - adds a key-value pair to the cache with TTL
-func (c *Cache) Set(_ context.Context, key, value string) error {
+// Set - adds a key-value pair to the cache with the default TTL
+func (c *Cache[K, V]) Set(ctx context.Context, key K, value V) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	c.mu.Lock()
-	el := elem{
-		value:    value,
-		exp_date: time.Now().Add(c.TTL), // Setting the lifetime
+	c.set(key, value, c.ttl, time.Now())
+	c.mu.Unlock()
+
+	return nil
+}
+
+// SetWithTTL - adds a key-value pair with its own lifetime (NoExpiration or 0 - never expires)
+func (c *Cache[K, V]) SetWithTTL(ctx context.Context, key K, value V, ttl time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
 	}
 
-	c.storage[key] = el
+	c.mu.Lock()
+	c.set(key, value, ttl, time.Now())
 	c.mu.Unlock()
 
 	return nil
 }
 
+// OnExpire - registers a hook called by the janitor for every expired element
+func (c *Cache[K, V]) OnExpire(fn func(key K, value V)) {
+	c.mu.Lock()
+	c.onExpire = fn
+	c.mu.Unlock()
+}
+
 // Stop - stops the background cache cleanup
-func (c *Cache) Stop() {
+func (c *Cache[K, V]) Stop() {
 	close(c.done)
 }
 
 // Get - returns the value for a key from the cache, checking the TTL
-func (c *Cache) Get(_ context.Context, key string) (string, error) {
+func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	var zero V
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	if c.sliding {
+		return c.getSliding(key)
+	}
 
 	c.mu.RLock()
 	el, ok := c.storage[key]
 	c.mu.RUnlock()
 
 	if !ok {
-		return "", ErrNotFound
+		c.misses.Add(1)
+		return zero, ErrNotFound
 	}
 
 	// Check if the lifetime has expired
-	if el.exp_date.Before(time.Now()) {
-		c.delete(key) // Remove the expired element
-		return "", ErrNotFound
+	if now := time.Now(); el.expired(now) {
+		c.misses.Add(1)
+		c.deleteExpired(key, now) // Remove the expired element
+		return zero, ErrNotFound
 	}
 
+	c.hits.Add(1)
 	return el.value, nil
 }
 
-// Del - removes an element from the cache
-func (c *Cache) Del(_ context.Context, key string) error {
+// TTL - returns the remaining lifetime of a key (NoExpiration if it never expires)
+func (c *Cache[K, V]) TTL(key K) (time.Duration, error) {
+	c.mu.RLock()
+	el, ok := c.storage[key]
+	c.mu.RUnlock()
+
+	now := time.Now()
+	if !ok || el.expired(now) {
+		return 0, ErrNotFound
+	}
+	if el.exp_date.IsZero() {
+		return NoExpiration, nil
+	}
+
+	return el.exp_date.Sub(now), nil
+}
+
+// Delete - removes an element from the cache
+func (c *Cache[K, V]) Delete(ctx context.Context, key K) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
 	c.delete(key)
 	return nil
 }
 
+// Len - returns the number of elements, including expired ones the janitor has not removed yet
+func (c *Cache[K, V]) Len() int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return len(c.storage)
+}
+
+// Stats - returns the hit, miss and expiration counters and the size
+func (c *Cache[K, V]) Stats() stats.Stats {
+	return stats.Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Expirations: c.expirations.Load(),
+		Size:        c.Len(),
+	}
+}
+
 // clearByTTL - starts the background cleanup of expired elements
-func (c *Cache) clearByTTL() {
-	ticker := time.NewTicker(10 * time.Second)
+func (c *Cache[K, V]) clearByTTL() {
+	ticker := time.NewTicker(c.sweep)
 
 	go func() {
+		defer ticker.Stop()
 		for {
 			select {
 			case <-ticker.C:
@@ -121,20 +303,97 @@ func (c *Cache) clearByTTL() {
 	}()
 }
 
+// set - stores an element and schedules its expiration (c.mu must be held)
+func (c *Cache[K, V]) set(key K, value V, ttl time.Duration, now time.Time) {
+	if ttl <= 0 {
+		ttl = NoExpiration
+	}
+	el := elem[V]{value: value, ttl: ttl}
+
+	if ttl == NoExpiration {
+		c.index.remove(key)
+	} else {
+		el.exp_date = now.Add(ttl) // Setting the lifetime
+		c.index.schedule(key, el.exp_date)
+	}
+
+	c.storage[key] = el
+}
+
+// getSliding - returns a value and restarts its lifetime (sliding expiration)
+func (c *Cache[K, V]) getSliding(key K) (V, error) {
+	var zero V
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.storage[key]
+	if !ok {
+		c.misses.Add(1)
+		return zero, ErrNotFound
+	}
+
+	now := time.Now()
+	if el.expired(now) {
+		c.misses.Add(1)
+		if c.onExpire == nil {
+			delete(c.storage, key)
+			c.index.remove(key)
+			c.expirations.Add(1)
+		}
+		return zero, ErrNotFound
+	}
+
+	if el.ttl != NoExpiration {
+		c.set(key, el.value, el.ttl, now)
+	}
+	c.hits.Add(1)
+	return el.value, nil
+}
+
 // delete - removes an element from the cache
-func (c *Cache) delete(key string) {
+func (c *Cache[K, V]) delete(key K) {
 	c.mu.Lock()
 	delete(c.storage, key)
+	c.index.remove(key)
 	c.mu.Unlock()
 }
 
-// clear - removes all expired elements from the cache
-func (c *Cache) clear() {
+// deleteExpired - removes an element only if it is still expired
+// (another goroutine may have set a new value after the read lock was released);
+// with an OnExpire hook the element is left to the janitor
+func (c *Cache[K, V]) deleteExpired(key K, now time.Time) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	for key, el := range c.storage {
-		if el.exp_date.Before(time.Now()) {
-			delete(c.storage, key)
+	if el, ok := c.storage[key]; ok && el.expired(now) && c.onExpire == nil {
+		delete(c.storage, key)
+		c.index.remove(key)
+		c.expirations.Add(1)
+	}
+	c.mu.Unlock()
+}
+
+// clear - removes the expired elements found by the expiry index: O(expired) instead of a full scan
+func (c *Cache[K, V]) clear() {
+	type expiredElem struct {
+		key   K
+		value V
+	}
+
+	var expired []expiredElem
+
+	c.mu.Lock()
+	onExpire := c.onExpire
+	c.index.expire(time.Now(), func(key K) {
+		if onExpire != nil {
+			expired = append(expired, expiredElem{key: key, value: c.storage[key].value})
 		}
+		delete(c.storage, key)
+		c.expirations.Add(1)
+	})
+	c.mu.Unlock()
+
+	// The hook is called without the lock, so it may use the cache itself
+	for _, e := range expired {
+		onExpire(e.key, e.value)
 	}
 }
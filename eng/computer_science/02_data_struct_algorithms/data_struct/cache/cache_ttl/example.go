@@ -1,6 +1,7 @@
 package cache_ttl
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"time"
@@ -9,7 +10,7 @@ import (
 // Example demonstrates the use of a cache with TTL
 func Example() {
 	// Create a cache with a 100 ms lifetime
-	cache := New(100 * time.Millisecond)
+	cache := New[string, string](100 * time.Millisecond)
 	ctx := context.Background()
 
 	// Add a value
@@ -36,4 +37,69 @@ func Example() {
 
 	// Stop the cleanup
 	cache.Stop()
+
+	// The janitor finds expired keys through the expiry index instead of scanning the whole map
+	for _, kind := range []IndexKind{HeapIndex, WheelIndex} {
+		sessions := New[int, string](50*time.Millisecond,
+			WithSweepInterval(20*time.Millisecond),
+			WithIndex(kind),
+		)
+
+		for i := 0; i < 1000; i++ {
+			sessions.Set(ctx, i, "session")
+		}
+		fmt.Printf("%s index: %d sessions stored\n", kind, sessions.Len())
+
+		time.Sleep(150 * time.Millisecond)
+		fmt.Printf("%s index: %d sessions left after the janitor ran\n", kind, sessions.Len())
+
+		sessions.Stop()
+	}
+
+	// Different lifetimes per key, sliding expiration and an expiry hook
+	tokens := New[string, string](time.Hour,
+		WithSweepInterval(10*time.Millisecond),
+		WithSlidingExpiration(),
+	)
+	defer tokens.Stop()
+
+	expired := make(chan string, 4)
+	tokens.OnExpire(func(key, value string) {
+		expired <- key // Called from the janitor goroutine
+	})
+
+	tokens.SetWithTTL(ctx, "otp:42", "123456", 30*time.Millisecond)
+	tokens.SetWithTTL(ctx, "session:7", "alice", 60*time.Millisecond)
+	tokens.SetWithTTL(ctx, "api-key", "secret", NoExpiration)
+	tokens.Set(ctx, "refresh:7", "token") // The default TTL of the cache: 1 hour
+
+	ttl, _ := tokens.TTL("api-key")
+	fmt.Printf("TTL(api-key) is NoExpiration: %t\n", ttl == NoExpiration)
+	ttl, _ = tokens.TTL("refresh:7")
+	fmt.Printf("TTL(refresh:7) is about an hour: %t\n", ttl > 59*time.Minute)
+
+	// The session is used every 20 ms, so its 60 ms lifetime keeps sliding forward
+	for i := 0; i < 5; i++ {
+		time.Sleep(20 * time.Millisecond)
+		tokens.Get(ctx, "session:7")
+	}
+
+	fmt.Printf("expired by the janitor: %s\n", <-expired)
+	_, err = tokens.Get(ctx, "session:7")
+	fmt.Printf("session:7 is alive after 100 ms thanks to sliding expiration: %t\n", err == nil)
+
+	// Snapshot and restore keep the remaining lifetimes
+	var snap bytes.Buffer
+	tokens.Snapshot(&snap)
+
+	restarted := New[string, string](time.Hour)
+	defer restarted.Stop()
+
+	err = restarted.Restore(&snap)
+	ttl, _ = restarted.TTL("session:7")
+	fmt.Printf("restored %d tokens (err: %v), session:7 has at most 60 ms left: %t\n",
+		restarted.Len(), err, ttl <= 60*time.Millisecond)
+
+	stats := tokens.Stats()
+	fmt.Printf("tokens: %d hits, %d misses, %d expired, %d stored\n", stats.Hits, stats.Misses, stats.Expirations, stats.Size)
 }
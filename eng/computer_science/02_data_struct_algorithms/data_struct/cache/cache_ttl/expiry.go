@@ -0,0 +1,106 @@
+package cache_ttl
+
+import (
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/heap"
+)
+
+// IndexKind - the data structure that tracks expiration times
+type IndexKind int
+
+const (
+	// HeapIndex - a min-heap ordered by expiration time: exact, O(log n) per Set
+	HeapIndex IndexKind = iota
+	// WheelIndex - a hierarchical timing wheel: O(1) per Set, expiration is rounded up to the sweep interval
+	WheelIndex
+)
+
+// String - returns the name of the index kind
+func (k IndexKind) String() string {
+	switch k {
+	case HeapIndex:
+		return "heap"
+	case WheelIndex:
+		return "wheel"
+	default:
+		return "unknown"
+	}
+}
+
+// expiryIndex - remembers when every key expires, so the janitor touches only expired keys
+type expiryIndex[K comparable] interface {
+	// schedule - adds a key or moves it to a new expiration time
+	schedule(key K, at time.Time)
+	// remove - forgets a key
+	remove(key K)
+	// expire - removes all keys expired by now and calls fn for each of them
+	expire(now time.Time, fn func(key K))
+}
+
+// newIndex - creates an expiry index of the given kind
+func newIndex[K comparable](kind IndexKind, start time.Time, tick time.Duration) expiryIndex[K] {
+	if kind == WheelIndex {
+		return newWheel[K](start, tick)
+	}
+	return newHeapIndex[K]()
+}
+
+// expiryItem - a key in the heap index
+type expiryItem[K comparable] struct {
+	key   K
+	at    time.Time
+	index int // Position in the heap, kept up to date by the heap itself
+}
+
+// heapIndex - a min-heap of expiration times with a key -> item map for updates and removals
+type heapIndex[K comparable] struct {
+	heap  *heap.Heap[*expiryItem[K]]
+	items map[K]*expiryItem[K]
+}
+
+// newHeapIndex - creates an empty heap index
+func newHeapIndex[K comparable]() *heapIndex[K] {
+	return &heapIndex[K]{
+		heap: heap.NewIndexed(
+			func(a, b *expiryItem[K]) bool { return a.at.Before(b.at) },
+			func(item *expiryItem[K], i int) { item.index = i },
+		),
+		items: make(map[K]*expiryItem[K]),
+	}
+}
+
+// schedule - pushes a new item or fixes the position of an existing one (O(log n))
+func (h *heapIndex[K]) schedule(key K, at time.Time) {
+	if item, ok := h.items[key]; ok {
+		item.at = at
+		h.heap.Fix(item.index)
+		return
+	}
+
+	item := &expiryItem[K]{key: key, at: at}
+	h.items[key] = item
+	h.heap.Push(item)
+}
+
+// remove - deletes an item by its remembered position (O(log n))
+func (h *heapIndex[K]) remove(key K) {
+	if item, ok := h.items[key]; ok {
+		h.heap.Remove(item.index)
+		delete(h.items, key)
+	}
+}
+
+// expire - pops items while the earliest one is expired (O(expired * log n))
+func (h *heapIndex[K]) expire(now time.Time, fn func(key K)) {
+	for {
+		item, ok := h.heap.Peek()
+		if !ok || !item.at.Before(now) {
+			return
+		}
+
+		h.heap.Pop()
+		delete(h.items, item.key)
+		fn(item.key)
+	}
+}
@@ -0,0 +1,62 @@
+package cache_ttl
+
+import (
+	"io"
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/snapshot"
+)
+
+// Snapshot - writes all live elements with their remaining lifetimes (see data_struct/cache/snapshot).
+// Keys and values must be gob-encodable.
+func (c *Cache[K, V]) Snapshot(w io.Writer) error {
+	now := time.Now()
+
+	c.mu.RLock()
+	records := make([]snapshot.Record[K, V], 0, len(c.storage))
+	for key, el := range c.storage {
+		if el.expired(now) {
+			continue
+		}
+
+		rec := snapshot.Record[K, V]{Key: key, Value: el.value, TTL: el.ttl}
+		if !el.exp_date.IsZero() {
+			rec.Remaining = max(el.exp_date.Sub(now), time.Nanosecond)
+		}
+		records = append(records, rec)
+	}
+	c.mu.RUnlock()
+
+	return snapshot.Write(w, snapshot.KindTTL, records)
+}
+
+// Restore - replaces the content of the cache with a snapshot.
+// Every element gets the lifetime it had left when the snapshot was written, counted from now.
+func (c *Cache[K, V]) Restore(r io.Reader) error {
+	records, err := snapshot.Read[K, V](r, snapshot.KindTTL)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.storage {
+		c.index.remove(key)
+	}
+	c.storage = make(map[K]elem[V], len(records))
+
+	for _, rec := range records {
+		el := elem[V]{value: rec.Value, ttl: rec.TTL}
+		if rec.Remaining > 0 {
+			el.exp_date = now.Add(rec.Remaining)
+			c.index.schedule(rec.Key, el.exp_date)
+		} else {
+			el.ttl = NoExpiration
+		}
+		c.storage[rec.Key] = el
+	}
+	return nil
+}
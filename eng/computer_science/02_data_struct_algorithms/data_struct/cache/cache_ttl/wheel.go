@@ -0,0 +1,126 @@
+package cache_ttl
+
+import "time"
+
+const (
+	wheelBits   = 6              // 64 slots per level
+	wheelSlots  = 1 << wheelBits // Slots per level
+	wheelMask   = wheelSlots - 1
+	wheelLevels = 4 // 64^4 ticks: about 194 days with a one-second tick
+	wheelSpan   = uint64(1) << (wheelBits * wheelLevels)
+)
+
+// wheelPos - where a key is stored in the wheel
+type wheelPos struct {
+	level, slot int
+	deadline    uint64 // Expiration time in ticks since the start
+}
+
+// wheel - a hierarchical timing wheel (like the timers of the Linux kernel and Kafka).
+//
+// Level 0 has one slot per tick, level 1 has one slot per 64 ticks, level 2 per 64^2 ticks and so on.
+// A key is put into the level that matches the distance to its deadline. When the lower level
+// completes a turn, the next slot of the upper level is cascaded: its keys are spread over the lower levels.
+// Scheduling and removal are O(1); every tick processes one slot of level 0,
+// so the cleanup costs O(expired) plus the amortized cascading.
+type wheel[K comparable] struct {
+	start   time.Time
+	tick    time.Duration
+	current uint64 // Ticks processed so far
+	levels  [wheelLevels][wheelSlots]map[K]struct{}
+	keys    map[K]wheelPos
+}
+
+// newWheel - creates an empty wheel; deadlines are rounded up to whole ticks
+func newWheel[K comparable](start time.Time, tick time.Duration) *wheel[K] {
+	w := &wheel[K]{
+		start: start,
+		tick:  max(tick, time.Millisecond),
+		keys:  make(map[K]wheelPos),
+	}
+	for level := range w.levels {
+		for slot := range w.levels[level] {
+			w.levels[level][slot] = make(map[K]struct{})
+		}
+	}
+	return w
+}
+
+// ticks - converts a time to ticks since the start, rounding up (a key never expires early)
+func (w *wheel[K]) ticks(t time.Time) uint64 {
+	d := t.Sub(w.start)
+	if d <= 0 {
+		return 0
+	}
+	return uint64((d + w.tick - 1) / w.tick)
+}
+
+// schedule - puts a key into the slot of its deadline (O(1))
+func (w *wheel[K]) schedule(key K, at time.Time) {
+	w.remove(key)
+	w.place(key, max(w.ticks(at), w.current+1))
+}
+
+// remove - takes a key out of its slot (O(1))
+func (w *wheel[K]) remove(key K) {
+	if pos, ok := w.keys[key]; ok {
+		delete(w.levels[pos.level][pos.slot], key)
+		delete(w.keys, key)
+	}
+}
+
+// place - chooses the level by the distance to the deadline and the slot by the deadline bits of that level
+func (w *wheel[K]) place(key K, deadline uint64) {
+	// Deadlines beyond the top level are parked at its farthest slot and re-placed when it cascades
+	target := min(deadline, w.current+wheelSpan-1)
+	delta := target - w.current
+
+	level := 0
+	for level < wheelLevels-1 && delta >= uint64(1)<<(wheelBits*(level+1)) {
+		level++
+	}
+	slot := int(target>>(wheelBits*level)) & wheelMask
+
+	w.levels[level][slot][key] = struct{}{}
+	w.keys[key] = wheelPos{level: level, slot: slot, deadline: deadline}
+}
+
+// expire - advances the wheel tick by tick up to now and calls fn for every expired key
+func (w *wheel[K]) expire(now time.Time, fn func(key K)) {
+	// Only whole ticks that have fully passed are processed
+	target := uint64(0)
+	if d := now.Sub(w.start); d > 0 {
+		target = uint64(d / w.tick)
+	}
+
+	for w.current < target {
+		w.current++
+
+		// Cascade the upper levels whose lower level has just completed a turn (the highest first)
+		for level := wheelLevels - 1; level > 0; level-- {
+			if w.current&(uint64(1)<<(wheelBits*level)-1) == 0 {
+				w.cascade(level, int(w.current>>(wheelBits*level))&wheelMask)
+			}
+		}
+
+		slot := w.levels[0][w.current&wheelMask]
+		for key := range slot {
+			delete(slot, key)
+			delete(w.keys, key)
+			fn(key)
+		}
+	}
+}
+
+// cascade - moves the keys of an upper-level slot down to the levels that match their deadlines
+func (w *wheel[K]) cascade(level, slot int) {
+	keys := w.levels[level][slot]
+	if len(keys) == 0 {
+		return
+	}
+
+	w.levels[level][slot] = make(map[K]struct{})
+	for key := range keys {
+		w.place(key, w.keys[key].deadline)
+	}
+}
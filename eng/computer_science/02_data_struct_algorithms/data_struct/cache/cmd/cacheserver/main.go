@@ -0,0 +1,76 @@
+// Command cacheserver serves a sharded cache over the Redis protocol (RESP).
+//
+// Usage:
+//
+//	cacheserver -addr :6380 -shards 16 -shard-capacity 100000
+//	redis-cli -p 6380 SET greeting hello EX 60
+//
+// With -metrics-addr the cache statistics are served in the Prometheus text format at /metrics.
+//
+// SIGINT or SIGTERM stops accepting connections and waits for the current commands to finish.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/cache_server"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/cache_shard"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/stats"
+)
+
+func main() {
+	addr := flag.String("addr", ":6380", "TCP address to listen on")
+	shards := flag.Int64("shards", 16, "number of cache shards")
+	shardCapacity := flag.Int("shard-capacity", 0, "max entries per shard, 0 - unlimited")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 10*time.Second, "how long to wait for connections on shutdown")
+	metricsAddr := flag.String("metrics-addr", "", "HTTP address for /metrics (Prometheus text format), empty - disabled")
+	flag.Parse()
+
+	c := cache_shard.New[string](*shards, cache_shard.WithShardCapacity(*shardCapacity))
+	server := cache_server.New(c)
+
+	if *metricsAddr != "" {
+		registry := stats.NewRegistry()
+		registry.Register("cacheserver", c)
+
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", registry)
+		go func() {
+			log.Printf("cacheserver: metrics: %v", http.ListenAndServe(*metricsAddr, mux))
+		}()
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- server.ListenAndServe(*addr)
+	}()
+	log.Printf("cacheserver: listening on %s with %d shards", *addr, *shards)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-served:
+		log.Fatalf("cacheserver: %v", err)
+	case sig := <-stop:
+		log.Printf("cacheserver: %v, shutting down", sig)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("cacheserver: shutdown: %v", err)
+	}
+	if err := <-served; !errors.Is(err, cache_server.ErrServerClosed) {
+		log.Printf("cacheserver: %v", err)
+	}
+}
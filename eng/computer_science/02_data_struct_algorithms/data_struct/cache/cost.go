@@ -0,0 +1,25 @@
+package cache
+
+// ErrTooLarge - the value costs more than a single item may cost, so the cache rejected it
+const ErrTooLarge = Error("cache: item exceeds the cost budget")
+
+// Sizer - a value that reports its own cost (for example, its size in bytes)
+type Sizer interface {
+	Size() int64
+}
+
+// DefaultCost - estimates the cost of a value for the caches with a cost budget:
+// the length of a string or a byte slice, Size() of a Sizer and 1 for anything else
+// (so a budget over other values counts entries).
+func DefaultCost[V any](value V) int64 {
+	switch v := any(value).(type) {
+	case string:
+		return int64(len(v))
+	case []byte:
+		return int64(len(v))
+	case Sizer:
+		return v.Size()
+	default:
+		return 1
+	}
+}
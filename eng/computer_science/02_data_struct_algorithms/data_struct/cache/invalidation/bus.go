@@ -0,0 +1,93 @@
+package invalidation
+
+import "sync"
+
+// Kind - what an invalidation event removes
+type Kind int
+
+const (
+	KeyEvent    Kind = iota // One key
+	TagEvent                // Every entry with a tag
+	PrefixEvent             // Every key with a prefix
+)
+
+// String - returns the name of the event kind
+func (k Kind) String() string {
+	switch k {
+	case KeyEvent:
+		return "key"
+	case TagEvent:
+		return "tag"
+	case PrefixEvent:
+		return "prefix"
+	default:
+		return "unknown"
+	}
+}
+
+// Event - an invalidation event
+type Event struct {
+	Kind  Kind
+	Value string // The key, the tag or the prefix
+}
+
+// Bus - an in-process publish/subscribe bus for invalidation events
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[int]func(Event)
+	nextID      int
+}
+
+// NewBus - creates a bus without subscribers
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[int]func(Event))}
+}
+
+// Subscribe - registers a handler of events, returns the function that unsubscribes it
+func (b *Bus) Subscribe(fn func(Event)) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = fn
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, id)
+			b.mu.Unlock()
+		})
+	}
+}
+
+// Publish - delivers an event to every subscriber and returns when all of them have handled it.
+// The handlers are called without the bus lock, so they may subscribe, unsubscribe and publish.
+func (b *Bus) Publish(e Event) {
+	b.mu.RLock()
+	handlers := make([]func(Event), 0, len(b.subscribers))
+	for _, fn := range b.subscribers {
+		handlers = append(handlers, fn)
+	}
+	b.mu.RUnlock()
+
+	for _, fn := range handlers {
+		fn(e)
+	}
+}
+
+// InvalidateKey - publishes the removal of one key
+func (b *Bus) InvalidateKey(key string) {
+	b.Publish(Event{Kind: KeyEvent, Value: key})
+}
+
+// InvalidateTag - publishes the removal of every entry with a tag
+func (b *Bus) InvalidateTag(tag string) {
+	b.Publish(Event{Kind: TagEvent, Value: tag})
+}
+
+// InvalidatePrefix - publishes the removal of every key with a prefix
+func (b *Bus) InvalidatePrefix(prefix string) {
+	b.Publish(Event{Kind: PrefixEvent, Value: prefix})
+}
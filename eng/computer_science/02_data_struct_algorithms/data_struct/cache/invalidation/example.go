@@ -0,0 +1,72 @@
+package invalidation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/LRU"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/cache_shard"
+)
+
+// Example demonstrates tag and prefix invalidation of two caches over one bus
+func Example() {
+	ctx := context.Background()
+
+	// Two caches of one process subscribed to the same bus
+	bus := NewBus()
+
+	// The evictions of the LRU are forwarded to Forget, so the indexes do not keep evicted keys
+	var pages *Cache[string]
+	pages = New[string](lru.New[string, string](100, lru.WithOnEvict(func(key, _ string) {
+		pages.Forget(key)
+	})), WithBus(bus))
+	defer pages.Close()
+
+	profiles := New[string](cache_shard.New[string](4), WithBus(bus))
+	defer profiles.Close()
+
+	// Entries built from the data of user 42 are tagged with it
+	pages.SetWithTags(ctx, "page:/users/42", "<html>alice</html>", "user:42")
+	pages.SetWithTags(ctx, "page:/orders?user=42", "<html>orders</html>", "user:42", "orders")
+	pages.SetWithTags(ctx, "page:/users/7", "<html>bob</html>", "user:7")
+	profiles.SetWithTags(ctx, "profile:42", "alice", "user:42")
+
+	// Sessions are grouped by the key prefix
+	for _, key := range []string{"session:a", "session:b", "session:c"} {
+		profiles.Set(ctx, key, "token")
+	}
+	profiles.Set(ctx, "settings:42", "dark")
+
+	fmt.Printf("tags of page:/orders?user=42: %v\n", pages.Tags("page:/orders?user=42"))
+
+	// Local invalidation of one cache
+	n, _ := pages.InvalidateTag(ctx, "orders")
+	fmt.Printf("InvalidateTag(orders) on pages: %d removed\n", n)
+
+	n, _ = profiles.InvalidatePrefix(ctx, "session:")
+	_, err := profiles.Get(ctx, "settings:42")
+	fmt.Printf("InvalidatePrefix(session:) on profiles: %d removed, settings:42 kept: %t\n", n, err == nil)
+
+	// User 42 changed the profile: one event clears both caches
+	bus.InvalidateTag("user:42")
+
+	_, err = pages.Get(ctx, "page:/users/42")
+	fmt.Printf("page:/users/42 after the bus event: %v\n", err)
+	_, err = profiles.Get(ctx, "profile:42")
+	fmt.Printf("profile:42 after the bus event: not found %t\n", errors.Is(err, cache.ErrNotFound))
+	_, err = pages.Get(ctx, "page:/users/7")
+	fmt.Printf("page:/users/7 of another user is kept: %t\n", err == nil)
+
+	// A prefix event through the bus
+	bus.InvalidatePrefix("page:")
+	_, err = pages.Get(ctx, "page:/users/7")
+	fmt.Printf("page:/users/7 after InvalidatePrefix(page:): not found %t\n", errors.Is(err, cache.ErrNotFound))
+
+	// A closed cache does not receive events any more
+	profiles.Close()
+	bus.InvalidateKey("settings:42")
+	_, err = profiles.Get(ctx, "settings:42")
+	fmt.Printf("settings:42 after an event for an unsubscribed cache is kept: %t\n", err == nil)
+}
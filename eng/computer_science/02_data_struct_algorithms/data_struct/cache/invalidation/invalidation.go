@@ -0,0 +1,334 @@
+/*
+Cache Invalidation by Tags and Prefixes
+
+What is it?
+A decorator over any cache with string keys that remembers which entries belong together,
+so a whole group can be removed at once:
+- by tag — every entry set with the tag "user:42" (the profile, the orders, the avatar of the user);
+- by prefix — every key that starts with "session:".
+A Bus delivers invalidation events to all the caches of a process that subscribed to it.
+
+Why is it needed?
+- When a user changes their profile, every cached view built from it must go, but the views have different keys.
+- The caches of this repository expose no iteration over their keys (a sharded cache, a TTL cache),
+  and a full scan would lock them anyway. An index kept on the side finds the group in O(group size).
+- A process often has several caches over the same data (per-handler caches, a loading cache, a page cache).
+  Without a bus, the code that changes the data has to know every one of them.
+
+What's the point?
+- Tags: SetWithTags stores the tags of an entry; two indexes are kept: tag -> keys and key -> tags.
+  InvalidateTag deletes the keys of a tag and removes them from all the other tags too.
+- Prefixes: every tracked key is in a trie (data_struct/trie). InvalidatePrefix walks only the subtree of the prefix,
+  so purging "session:" does not touch the millions of "product:" keys. The trie walks runes, so keys are stored
+  one byte per rune: any byte string is a valid key, and invalid UTF-8 never turns into U+FFFD there.
+- The wrapped cache may evict or expire entries on its own: the index keeps those keys until they are set again,
+  invalidated or passed to Forget (connect it to the OnEvict callback of the cache to keep the index tight).
+  Forget only queues the key: OnEvict is called during a Set of this very cache, when the indexes are locked.
+  A queued key is dropped only if it was not set again since Forget (every Set gives the key a new version)
+  and the wrapped cache does not hold it any more (checked with Peek or TTL if the cache has them, without
+  touching the entry): a janitor may report an old entry after the key was already set again.
+- Bus: Publish calls every subscriber synchronously, so when it returns, no subscribed cache serves
+  the invalidated entries any more. Subscribers are called outside the bus lock and may publish themselves.
+
+When to use?
+- Derived data with clear ownership (everything of a user, of a tenant, of a product).
+- Key spaces structured by prefixes (namespace:id:field).
+
+### Complexity
+
+| Operation | Time Complexity (O) |
+|:---|:---:|
+| Set / SetWithTags / Delete | O(cache) + O(m + t) |
+| InvalidateTag | O(k * (cache + m + t)) |
+| InvalidatePrefix | O(p + k * (cache + m + t)) |
+
+*m — key length, t — number of tags of an entry, k — number of removed keys, p — total length of the keys under the prefix.
+
+Examples of using tag and prefix invalidation:
+See the example.go file.
+*/
+
+package invalidation
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/trie"
+)
+
+// Cache - a cache with string keys, tags per entry and group invalidation
+type Cache[V any] struct {
+	cache cache.Cache[string, V]
+
+	mu      sync.Mutex
+	tags    map[string]map[string]struct{} // Tag -> keys
+	keyTags map[string][]string            // Key -> its tags
+	keys    *trie.Trie                     // All tracked keys (encoded by trieKey), for prefix invalidation
+
+	forgetMu  sync.Mutex
+	forgotten []forgotten           // Keys passed to Forget, dropped from the indexes by the next operation
+	versions  map[string]uint64     // Tracked key -> the version of its last Set (guarded by forgetMu)
+	version   uint64                // The last given version
+	holds     func(key string) bool // Whether the wrapped cache still holds a key (false if it cannot tell)
+
+	unsubscribe func()
+}
+
+var _ cache.Cache[string, int] = (*Cache[int])(nil)
+
+// forgotten - a key passed to Forget and the version it had then
+type forgotten struct {
+	key     string
+	version uint64
+}
+
+// options - settings of an invalidating cache
+type options struct {
+	bus *Bus
+}
+
+// Option - configures an invalidating cache
+type Option func(*options)
+
+// WithBus - subscribes the cache to the invalidation events of a bus
+func WithBus(b *Bus) Option {
+	return func(o *options) {
+		o.bus = b
+	}
+}
+
+// New - wraps a cache (lru.LRU, cache_ttl.Cache, cache_shard.Cache, ...)
+func New[V any](c cache.Cache[string, V], opts ...Option) *Cache[V] {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ic := &Cache[V]{
+		cache:    c,
+		tags:     make(map[string]map[string]struct{}),
+		keyTags:  make(map[string][]string),
+		keys:     trie.NewTrie(),
+		versions: make(map[string]uint64),
+	}
+
+	// Whether the wrapped cache still holds a key, asked without counting a hit or moving the entry
+	switch h := c.(type) {
+	case interface{ Peek(key string) (V, bool) }: // lru.LRU, lfu, arc, two_queue, tinylfu
+		ic.holds = func(key string) bool {
+			_, ok := h.Peek(key)
+			return ok
+		}
+	case interface {
+		TTL(key string) (time.Duration, error)
+	}: // cache_ttl.Cache, cache_shard.Cache
+		ic.holds = func(key string) bool {
+			_, err := h.TTL(key)
+			return err == nil
+		}
+	default:
+		ic.holds = func(string) bool { return false }
+	}
+	if o.bus != nil {
+		ic.unsubscribe = o.bus.Subscribe(ic.apply)
+	}
+
+	return ic
+}
+
+// Get - returns the value for a key from the wrapped cache
+func (c *Cache[V]) Get(ctx context.Context, key string) (V, error) {
+	return c.cache.Get(ctx, key)
+}
+
+// Set - stores a value without tags (the previous tags of the key are dropped)
+func (c *Cache[V]) Set(ctx context.Context, key string, value V) error {
+	return c.SetWithTags(ctx, key, value)
+}
+
+// SetWithTags - stores a value with a set of tags, replacing the previous tags of the key
+func (c *Cache[V]) SetWithTags(ctx context.Context, key string, value V, tags ...string) error {
+	c.lock()
+	defer c.mu.Unlock()
+
+	// Under the lock, so a concurrent invalidation sees either the old entry with its tags or the new one
+	if err := c.cache.Set(ctx, key, value); err != nil {
+		return err
+	}
+
+	c.untrack(key)
+	c.track(key, tags)
+	return nil
+}
+
+// Delete - removes a key from the cache and from the indexes
+func (c *Cache[V]) Delete(ctx context.Context, key string) error {
+	c.lock()
+	defer c.mu.Unlock()
+
+	if err := c.cache.Delete(ctx, key); err != nil {
+		return err
+	}
+
+	c.untrack(key)
+	return nil
+}
+
+// Tags - returns the tags of a key
+func (c *Cache[V]) Tags(key string) []string {
+	c.lock()
+	defer c.mu.Unlock()
+
+	return append([]string(nil), c.keyTags[key]...)
+}
+
+// InvalidateTag - removes every entry set with the tag, returns the number of removed keys
+func (c *Cache[V]) InvalidateTag(ctx context.Context, tag string) (int, error) {
+	c.lock()
+	defer c.mu.Unlock()
+
+	keys := make([]string, 0, len(c.tags[tag]))
+	for key := range c.tags[tag] {
+		keys = append(keys, key)
+	}
+	return c.invalidate(ctx, keys)
+}
+
+// InvalidatePrefix - removes every entry whose key starts with the prefix, returns the number of removed keys
+func (c *Cache[V]) InvalidatePrefix(ctx context.Context, prefix string) (int, error) {
+	c.lock()
+	defer c.mu.Unlock()
+
+	keys := c.keys.WithPrefix(trieKey(prefix))
+	for i, key := range keys {
+		keys[i] = fromTrieKey(key)
+	}
+	return c.invalidate(ctx, keys)
+}
+
+// Forget - drops a key from the indexes without touching the cache.
+// Call it from the OnEvict or OnExpire callback of the wrapped cache to keep the indexes small.
+// The key is queued and dropped by the next operation, so Forget never waits for the indexes.
+func (c *Cache[V]) Forget(key string) {
+	c.forgetMu.Lock()
+	defer c.forgetMu.Unlock()
+
+	c.forgotten = append(c.forgotten, forgotten{key: key, version: c.versions[key]})
+}
+
+// Close - unsubscribes the cache from its bus
+func (c *Cache[V]) Close() {
+	if c.unsubscribe != nil {
+		c.unsubscribe()
+	}
+}
+
+// apply - handles an event of the bus
+func (c *Cache[V]) apply(e Event) {
+	ctx := context.Background()
+
+	switch e.Kind {
+	case KeyEvent:
+		c.Delete(ctx, e.Value)
+	case TagEvent:
+		c.InvalidateTag(ctx, e.Value)
+	case PrefixEvent:
+		c.InvalidatePrefix(ctx, e.Value)
+	}
+}
+
+// lock - takes c.mu and drops the keys queued by Forget from the indexes
+func (c *Cache[V]) lock() {
+	c.mu.Lock()
+
+	c.forgetMu.Lock()
+	forgotten := c.forgotten
+	c.forgotten = nil
+	c.forgetMu.Unlock()
+
+	for _, f := range forgotten {
+		c.forgetMu.Lock()
+		version, tracked := c.versions[f.key]
+		c.forgetMu.Unlock()
+
+		if !tracked || version != f.version {
+			continue // Already dropped, or set again after Forget
+		}
+		if c.holds(f.key) {
+			continue // Forget was about an older entry: the key was set again before Forget was called
+		}
+		c.untrack(f.key)
+	}
+}
+
+// invalidate - deletes keys from the cache and the indexes (c.mu must be held)
+func (c *Cache[V]) invalidate(ctx context.Context, keys []string) (int, error) {
+	for i, key := range keys {
+		if err := c.cache.Delete(ctx, key); err != nil {
+			return i, err
+		}
+		c.untrack(key)
+	}
+	return len(keys), nil
+}
+
+// track - adds a key to the indexes (c.mu must be held)
+func (c *Cache[V]) track(key string, tags []string) {
+	c.keys.Insert(trieKey(key))
+
+	c.forgetMu.Lock()
+	c.version++
+	c.versions[key] = c.version
+	c.forgetMu.Unlock()
+
+	if len(tags) == 0 {
+		return
+	}
+
+	c.keyTags[key] = append([]string(nil), tags...)
+	for _, tag := range tags {
+		if c.tags[tag] == nil {
+			c.tags[tag] = make(map[string]struct{})
+		}
+		c.tags[tag][key] = struct{}{}
+	}
+}
+
+// untrack - removes a key from the indexes (c.mu must be held)
+func (c *Cache[V]) untrack(key string) {
+	c.keys.Delete(trieKey(key))
+
+	c.forgetMu.Lock()
+	delete(c.versions, key)
+	c.forgetMu.Unlock()
+
+	for _, tag := range c.keyTags[key] {
+		delete(c.tags[tag], key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
+	delete(c.keyTags, key)
+}
+
+// trieKey - encodes a key for the trie one byte per rune (U+0000..U+00FF), so byte prefixes stay rune prefixes
+// and two different keys never share a path
+func trieKey(key string) string {
+	runes := make([]rune, len(key))
+	for i := 0; i < len(key); i++ {
+		runes[i] = rune(key[i])
+	}
+	return string(runes)
+}
+
+// fromTrieKey - decodes a key encoded by trieKey
+func fromTrieKey(s string) string {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		b = append(b, byte(r))
+	}
+	return string(b)
+}
@@ -0,0 +1,68 @@
+package loading_cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Example demonstrates the use of a loading cache
+func Example() {
+	ctx := context.Background()
+
+	// A "database" that is slow and counts its queries
+	var queries atomic.Int64
+	db := func(ctx context.Context, id int) (string, error) {
+		queries.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		if id < 0 {
+			return "", errors.New("db: invalid id")
+		}
+		return fmt.Sprintf("user-%d", id), nil
+	}
+
+	users := New(db,
+		WithTTL(50*time.Millisecond),
+		WithStaleWhileRevalidate(time.Second),
+		WithNegativeTTL(100*time.Millisecond),
+	)
+	defer users.Stop()
+
+	// 100 concurrent misses for the same key: one query
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			users.Get(ctx, 1)
+		}()
+	}
+	wg.Wait()
+	fmt.Printf("100 concurrent Get(1): %d query\n", queries.Load())
+
+	// After the TTL the value is stale: it is returned at once and refreshed in the background
+	time.Sleep(60 * time.Millisecond)
+	start := time.Now()
+	name, _ := users.Get(ctx, 1)
+	fmt.Printf("stale Get(1) = %s without waiting for the db: %t\n", name, time.Since(start) < 10*time.Millisecond)
+
+	time.Sleep(40 * time.Millisecond)
+	fmt.Printf("queries after the background refresh: %d\n", queries.Load())
+
+	// Errors are cached: a failing query is not repeated by every request
+	for i := 0; i < 10; i++ {
+		_, err := users.Get(ctx, -1)
+		if i == 0 {
+			fmt.Printf("Get(-1): %v\n", err)
+		}
+	}
+	fmt.Printf("queries after 10 failing Get(-1): %d\n", queries.Load())
+
+	// Load latency and errors next to the hit ratio
+	stats := users.Stats()
+	fmt.Printf("hit ratio %.2f, %d loads (%d failed), mean load %v\n",
+		stats.HitRatio(), stats.LoadLatency.Count, stats.LoadErrors, stats.LoadLatency.Mean().Round(time.Millisecond))
+}
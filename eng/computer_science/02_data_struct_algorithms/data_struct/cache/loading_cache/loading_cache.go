@@ -0,0 +1,268 @@
+/*
+Loading Cache
+
+What is it?
+A loading (read-through) cache knows how to get a missing value by itself: it is created with a Loader function,
+and Get calls the loader on a miss and stores the result. The caller never writes "get, miss, load, set" by hand.
+
+Why is it needed?
+- Cache stampede: when a popular key expires, hundreds of requests miss at the same moment
+  and all of them go to the database for the same value.
+- Latency spikes: every expiration of a popular key makes one unlucky request wait for the load.
+- Failure amplification: when the database fails, every request retries the failing query immediately.
+
+What's the point?
+- Deduplication: concurrent misses for the same key share one call of the loader (singleflight from golang.org/x/sync).
+- Stale-while-revalidate: after the TTL an entry becomes stale, not missing. A stale value is returned immediately
+  while one background refresh loads a fresh value. Only after the stale period the entry is gone for good.
+- Negative caching: a loader error is remembered for a short time (WithNegativeTTL),
+  so a failing dependency receives one request per key per period instead of all of them.
+
+When to use?
+- A cache in front of a database or a remote service that is read much more often than written.
+- Popular keys with expensive loads.
+
+How does it work?
+- Entries are stored in a cache_ttl.Cache with the lifetime TTL + stale period.
+  Every entry remembers until when it is fresh.
+- Get:
+  - fresh entry — returned as is (or its cached error);
+  - stale entry — returned as is, and a background refresh is started (at most one per key);
+  - no entry — the loader is called through singleflight; all concurrent callers of this key wait for it.
+- The shared load runs with a context detached from the cancellation of the first caller,
+  so one canceled request does not fail the others; every caller still stops waiting on its own ctx.
+
+### Complexity
+
+| Operation | Time Complexity (O) |
+|:---|:---:|
+| Get (hit, fresh or stale) | O(log n)* |
+| Get (miss) | one call of the loader per key, however many callers |
+
+*The cost of the cache_ttl expiry index.
+
+LoadingCache[K, V] implements the common cache.Cache interface (see data_struct/cache).
+Stats adds the latency histogram and the error count of the loader to the counters of cache_ttl.
+
+Examples of using the loading cache:
+See the example.go file.
+*/
+
+package loading_cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/cache_ttl"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/stats"
+)
+
+const defaultTTL = time.Minute
+
+// Loader - loads the value of a key from the source of truth (a database, a remote service)
+type Loader[K comparable, V any] func(ctx context.Context, key K) (V, error)
+
+// result - a loaded value or a loader error, with the time until which it is fresh
+type result[V any] struct {
+	value V
+	err   error
+	fresh time.Time
+}
+
+// LoadingCache - a read-through cache with load deduplication, stale-while-revalidate and negative caching
+type LoadingCache[K comparable, V any] struct {
+	cache       *cache_ttl.Cache[K, result[V]]
+	loader      Loader[K, V]
+	group       singleflight.Group // Loads on a miss, their errors are cached
+	refreshes   singleflight.Group // Background refreshes, their errors are not: the stale value is kept
+	ttl         time.Duration      // How long a loaded value is fresh
+	stale       time.Duration      // How long an expired value may still be served while it is refreshed
+	negativeTTL time.Duration      // How long a loader error is cached, 0 - not cached
+	latency     *stats.Histogram
+	loadErrors  atomic.Uint64
+}
+
+var _ cache.Cache[string, int] = (*LoadingCache[string, int])(nil)
+
+// options - settings of a loading cache
+type options struct {
+	ttl          time.Duration
+	stale        time.Duration
+	negativeTTL  time.Duration
+	cacheOptions []cache_ttl.Option
+}
+
+// Option - configures a loading cache
+type Option func(*options)
+
+// WithTTL - sets how long a loaded value is fresh (1 minute by default, non-positive values are ignored:
+// a value that is never fresh would be refreshed on every Get)
+func WithTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		if ttl > 0 {
+			o.ttl = ttl
+		}
+	}
+}
+
+// WithStaleWhileRevalidate - sets how long after the TTL a value is still served while it is refreshed
+// (0 by default: an expired value is loaded again synchronously)
+func WithStaleWhileRevalidate(d time.Duration) Option {
+	return func(o *options) {
+		o.stale = d
+	}
+}
+
+// WithNegativeTTL - sets how long a loader error is cached (0 by default: errors are not cached)
+func WithNegativeTTL(d time.Duration) Option {
+	return func(o *options) {
+		o.negativeTTL = d
+	}
+}
+
+// WithCacheOptions - passes options to the underlying cache_ttl.Cache (sweep interval, expiry index)
+func WithCacheOptions(opts ...cache_ttl.Option) Option {
+	return func(o *options) {
+		o.cacheOptions = append(o.cacheOptions, opts...)
+	}
+}
+
+// New - creates a loading cache around a loader
+func New[K comparable, V any](loader Loader[K, V], opts ...Option) *LoadingCache[K, V] {
+	o := options{ttl: defaultTTL}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &LoadingCache[K, V]{
+		cache:       cache_ttl.New[K, result[V]](o.ttl+o.stale, o.cacheOptions...),
+		loader:      loader,
+		ttl:         o.ttl,
+		stale:       max(o.stale, 0),
+		negativeTTL: max(o.negativeTTL, 0),
+		latency:     stats.NewHistogram(),
+	}
+}
+
+// Get - returns a cached value, a stale value (refreshing it in the background) or loads it
+func (c *LoadingCache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	var zero V
+
+	r, err := c.cache.Get(ctx, key)
+	switch {
+	case err == nil && time.Now().Before(r.fresh):
+		return r.value, r.err
+
+	case err == nil && r.err == nil:
+		// Stale: serve the old value, refresh it once in the background
+		c.refresh(ctx, key)
+		return r.value, nil
+
+	case err != nil && !errors.Is(err, cache_ttl.ErrNotFound):
+		return zero, err // Canceled ctx
+	}
+
+	ch := c.group.DoChan(c.flightKey(key), func() (any, error) {
+		return c.load(context.WithoutCancel(ctx), key)
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		v, _ := res.Val.(V) // nil when V is an interface and the loader returned nil
+		return v, nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// Set - stores a fresh value directly (e.g. after a write to the source of truth)
+func (c *LoadingCache[K, V]) Set(ctx context.Context, key K, value V) error {
+	return c.cache.SetWithTTL(ctx, key, result[V]{value: value, fresh: time.Now().Add(c.ttl)}, c.ttl+c.stale)
+}
+
+// Delete - removes a value, the next Get loads it again
+func (c *LoadingCache[K, V]) Delete(ctx context.Context, key K) error {
+	return c.cache.Delete(ctx, key)
+}
+
+// Len - returns the number of cached values and errors, including stale ones
+func (c *LoadingCache[K, V]) Len() int {
+	return c.cache.Len()
+}
+
+// Stats - returns the counters of the underlying cache (a stale value counts as a hit)
+// together with the load latency histogram and the number of failed loads
+func (c *LoadingCache[K, V]) Stats() stats.Stats {
+	s := c.cache.Stats()
+	s.LoadErrors = c.loadErrors.Load()
+	s.LoadLatency = c.latency.Snapshot()
+	return s
+}
+
+// Stop - stops the background cleanup of the underlying cache
+func (c *LoadingCache[K, V]) Stop() {
+	c.cache.Stop()
+}
+
+// load - calls the loader and caches the value or the error
+func (c *LoadingCache[K, V]) load(ctx context.Context, key K) (V, error) {
+	value, err := c.call(ctx, key)
+	now := time.Now()
+
+	if err != nil {
+		if c.negativeTTL > 0 {
+			c.cache.SetWithTTL(ctx, key, result[V]{err: err, fresh: now.Add(c.negativeTTL)}, c.negativeTTL)
+		}
+		return value, err
+	}
+
+	c.cache.SetWithTTL(ctx, key, result[V]{value: value, fresh: now.Add(c.ttl)}, c.ttl+c.stale)
+	return value, nil
+}
+
+// refresh - reloads a stale value in the background; concurrent refreshes of a key are deduplicated.
+// Refreshes have their own group: a miss must not join a refresh, or it would get an error that is never cached.
+func (c *LoadingCache[K, V]) refresh(ctx context.Context, key K) {
+	ctx = context.WithoutCancel(ctx)
+
+	// DoChan starts the load in its own goroutine and only subscribes the later callers (the channel is buffered)
+	c.refreshes.DoChan(c.flightKey(key), func() (any, error) {
+		value, err := c.call(ctx, key)
+		if err != nil {
+			return nil, err // Keep serving the stale value until it is gone
+		}
+
+		c.cache.SetWithTTL(ctx, key, result[V]{value: value, fresh: time.Now().Add(c.ttl)}, c.ttl+c.stale)
+		return value, nil
+	})
+}
+
+// call - calls the loader and records its latency and errors
+func (c *LoadingCache[K, V]) call(ctx context.Context, key K) (V, error) {
+	start := time.Now()
+	value, err := c.loader(ctx, key)
+
+	c.latency.Observe(time.Since(start))
+	if err != nil {
+		c.loadErrors.Add(1)
+	}
+	return value, err
+}
+
+// flightKey - singleflight groups calls by string keys
+func (c *LoadingCache[K, V]) flightKey(key K) string {
+	if s, ok := any(key).(string); ok {
+		return s
+	}
+	return fmt.Sprintf("%#v", key)
+}
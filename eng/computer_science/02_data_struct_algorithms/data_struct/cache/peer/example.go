@@ -0,0 +1,100 @@
+package peer
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Example demonstrates a peer-to-peer cache of three nodes on httptest servers
+func Example() {
+	ctx := context.Background()
+
+	// The source of truth is slow and counts its loads across the whole cluster
+	var loads atomic.Int64
+	loader := func(ctx context.Context, key string) (string, error) {
+		loads.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return "value of " + key, nil
+	}
+
+	// 1. Three nodes in one process, each one is an HTTP server for its peers
+	nodes := make([]*Node[string], 3)
+	servers := make([]*httptest.Server, 3)
+	urls := make([]string, 3)
+	for i := range nodes {
+		nodes[i] = New(loader, WithHotThreshold(2), WithHotTTL(time.Minute))
+		servers[i] = httptest.NewServer(nodes[i])
+		urls[i] = servers[i].URL
+	}
+	defer func() {
+		for _, s := range servers {
+			s.Close()
+		}
+	}()
+	for i, node := range nodes {
+		node.SetPeers(urls[i], urls...)
+	}
+
+	// All nodes agree on the owners, the key space is split between them
+	owned := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		owned[nodes[0].Owner(fmt.Sprintf("key:%d", i))]++
+	}
+	agree := true
+	for i := 0; i < 3000; i++ {
+		key := fmt.Sprintf("key:%d", i)
+		agree = agree && nodes[1].Owner(key) == nodes[0].Owner(key) && nodes[2].Owner(key) == nodes[0].Owner(key)
+	}
+	fmt.Println("nodes agree on owners:", agree)
+	for i, url := range urls {
+		fmt.Printf("node %d owns %d of 3000 keys\n", i, owned[url])
+	}
+
+	// 2. 90 concurrent requests for one key spread over all nodes: one load in the whole cluster
+	var wg sync.WaitGroup
+	for i := 0; i < 90; i++ {
+		wg.Add(1)
+		go func(node *Node[string]) {
+			defer wg.Done()
+			node.Get(ctx, "user:42")
+		}(nodes[i%3])
+	}
+	wg.Wait()
+	fmt.Println("loads of user:42 after 90 concurrent requests:", loads.Load())
+
+	// 3. A hot key: a node that does not own it replicates it after two fetches from the owner
+	reader := nodes[0]
+	if reader.owns("user:42") {
+		reader = nodes[1]
+	}
+	before := reader.NodeStats()
+	for i := 0; i < 5; i++ {
+		reader.Get(ctx, "user:42")
+	}
+	after := reader.NodeStats()
+	fmt.Printf("5 reads on a non-owner: %d fetched from the owner, %d served by the hot replica\n",
+		after.PeerFetches-before.PeerFetches, after.HotHits-before.HotHits)
+
+	// The common counters: a node is a stats.Source, so it can be registered in a stats.Registry
+	s := reader.Stats()
+	fmt.Printf("reader: %d hits, %d misses, %d entries\n", s.Hits, s.Misses, s.Size)
+
+	// 4. The owner goes down: other nodes degrade to loading the key themselves
+	var owner int
+	for i, url := range urls {
+		if url == reader.Owner("report:7") {
+			owner = i
+		}
+	}
+	servers[owner].Close()
+	other := nodes[(owner+1)%3]
+
+	loads.Store(0)
+	value, err := other.Get(ctx, "report:7")
+	fmt.Printf("owner down: %q, err %v, local loads %d, peer errors %d\n",
+		value, err, loads.Load(), other.NodeStats().PeerErrors)
+}
@@ -0,0 +1,493 @@
+/*
+Peer-to-Peer Distributed Cache (groupcache-style)
+
+What is it?
+A cache spread over several nodes (processes) that talk to each other over HTTP.
+Every node owns a slice of the key space, and every key is loaded from the source of truth by its owner only.
+
+Why is it needed?
+- N independent local caches hold N copies of the same popular keys and load each key N times.
+- A separate cache cluster (Redis, memcached) is one more system to deploy and monitor.
+- In a peer-to-peer cache the application nodes are the cache: memory adds up, and each key is loaded once.
+
+What's the point?
+- Ownership: keys are placed on a consistent hash ring of the peer URLs (cache_shard.Ring).
+  All nodes build the same ring, so all of them agree on the owner of a key without any coordination.
+- A node asks the owner for a key it does not own (GET /_peer/<key>, JSON reply).
+- Deduplication across the cluster: concurrent requests for a key on one node share one fetch (singleflight),
+  and all fetches end at the owner, which shares one load. So a key is loaded once per cluster, not once per request.
+- Hot keys: a key that a node fetched from its owner several times (WithHotThreshold) is replicated locally
+  for a short time (WithHotTTL), so a very popular key does not overload its owner and the network.
+- If the owner is unreachable, the node loads the key itself (the service degrades, it does not fail).
+  If the owner is up but its loader fails, the owner replies 502 with the error and the node returns
+  ErrOwnerLoad: loading the key again on every node would multiply the load exactly when the source struggles.
+
+When to use?
+- Immutable or rarely changing data that is expensive to load (rendered pages, thumbnails, configuration blobs).
+- Not for data that changes often: there is no Set/invalidate across the cluster, like in groupcache.
+
+How does it work?
+- Get(key):
+  1. the local owned cache (keys of this node) and the hot cache (replicas);
+  2. singleflight by key;
+  3. the owner is this node — call the loader, keep the value in the owned cache;
+     the owner is a peer — fetch over HTTP, count the fetch, replicate the key if it is hot.
+- A request from a peer carries a header (X-Peer-Forwarded) that forbids forwarding it again: ServeHTTP loads
+  such a key locally even if its own ring names another owner, so nodes with different peer lists
+  during a rollout cannot bounce a request between themselves. A request without the header
+  (e.g. from a client that is not a peer) is served like Get and may go to the owner.
+
+Statistics:
+- Stats returns the common counters (hits, misses, evictions, load latency, ...), so a node can be registered
+  in a stats.Registry and exported to Prometheus; NodeStats returns the peer counters (fetches, errors, served).
+
+### Complexity
+
+| Operation | Cost |
+|:---|:---:|
+| Get (local hit) | O(log V) ring lookup + O(1) |
+| Get (owned miss) | one load per key in the whole cluster |
+| Get (remote) | one HTTP round trip per key per node at a time |
+
+Examples of using the peer-to-peer cache:
+See the example.go file (several nodes with httptest in one process).
+*/
+
+package peer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/LRU"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/cache_shard"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/stats"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
+)
+
+const (
+	// BasePath - the HTTP path prefix of peer requests
+	BasePath = "/_peer/"
+
+	// forwardedHeader - marks a request that came from a peer and must not be forwarded again
+	forwardedHeader = "X-Peer-Forwarded"
+
+	defaultVirtualNodes = 50
+	defaultHotThreshold = 3
+	defaultHotTTL       = time.Minute
+	defaultCapacity     = 10_000 // Entries per shard of the owned cache
+	hotCapacity         = 1_000  // Entries per shard of the hot cache
+	cacheShards         = 8
+)
+
+// ErrOwnerLoad - the owner of a key is reachable, but its loader failed (the message of the owner follows)
+const ErrOwnerLoad = cache.Error("peer: the owner failed to load the key")
+
+// Loader - loads the value of a key from the source of truth
+type Loader[V any] func(ctx context.Context, key string) (V, error)
+
+// reply - the JSON body of a peer response
+type reply[V any] struct {
+	Value V      `json:"value"`
+	Error string `json:"error,omitempty"`
+}
+
+// NodeStats - the peer-to-peer counters of a node
+type NodeStats struct {
+	OwnedHits   uint64 // Served from the owned cache
+	HotHits     uint64 // Served from the hot replicas
+	Loads       uint64 // Calls of the loader
+	PeerFetches uint64 // Successful fetches from the owners
+	PeerErrors  uint64 // Fetches from unreachable owners (followed by a local load)
+	PeerServed  uint64 // Requests served for other peers
+}
+
+// Node - one member of the peer-to-peer cache; it is also the http.Handler for peer requests
+type Node[V any] struct {
+	loader    Loader[V]
+	owned     *cache_shard.Cache[V] // Keys owned by this node
+	hot       *cache_shard.Cache[V] // Replicas of hot keys owned by other nodes
+	group     singleflight.Group    // Gets and loads by this node
+	forwarded singleflight.Group    // Loads of forwarded keys this node does not own by its ring (see getLocal)
+	client    *http.Client
+
+	mu     sync.RWMutex
+	self   string
+	ring   *cache_shard.Ring
+	peers  map[int]string        // Id on the ring -> peer base URL
+	hasher hasher.Hasher[string] // xxHash64 with a fixed seed: the ring must be identical on every node
+
+	fetches      *lru.LRU[string, int] // Remote fetch counters of recently requested keys
+	hotThreshold int
+	hotTTL       time.Duration
+	virtualNodes int
+
+	latency *stats.Histogram // Latency of the loader calls
+
+	ownedHits, hotHits, misses, loads, loadErrors, peerFetches, peerErrors, peerServed atomic.Uint64
+}
+
+var (
+	_ cache.Cache[string, int] = (*Node[int])(nil)
+	_ stats.Source             = (*Node[int])(nil)
+)
+
+// options - settings of a node
+type options struct {
+	client       *http.Client
+	capacity     int
+	hotThreshold int
+	hotTTL       time.Duration
+	virtualNodes int
+}
+
+// Option - configures a node
+type Option func(*options)
+
+// WithHTTPClient - sets the client for peer requests (http.DefaultClient by default)
+func WithHTTPClient(c *http.Client) Option {
+	return func(o *options) {
+		o.client = c
+	}
+}
+
+// WithCapacity - sets the number of owned entries per shard of the local cache (10 000 by default)
+func WithCapacity(n int) Option {
+	return func(o *options) {
+		o.capacity = n
+	}
+}
+
+// WithHotThreshold - the number of fetches from the owner after which a key is replicated locally (3 by default)
+func WithHotThreshold(n int) Option {
+	return func(o *options) {
+		o.hotThreshold = max(n, 1)
+	}
+}
+
+// WithHotTTL - how long a hot replica lives, it bounds the staleness of replicas (1 minute by default)
+func WithHotTTL(ttl time.Duration) Option {
+	return func(o *options) {
+		o.hotTTL = ttl
+	}
+}
+
+// WithVirtualNodes - ring points per peer (50 by default); must be the same on all nodes
+func WithVirtualNodes(n int) Option {
+	return func(o *options) {
+		o.virtualNodes = n
+	}
+}
+
+// New - creates a node; call SetPeers before Get to join a cluster
+func New[V any](loader Loader[V], opts ...Option) *Node[V] {
+	o := options{
+		client:       http.DefaultClient,
+		capacity:     defaultCapacity,
+		hotThreshold: defaultHotThreshold,
+		hotTTL:       defaultHotTTL,
+		virtualNodes: defaultVirtualNodes,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Node[V]{
+		loader:       loader,
+		owned:        cache_shard.New[V](cacheShards, cache_shard.WithShardCapacity(o.capacity)),
+		hot:          cache_shard.New[V](cacheShards, cache_shard.WithShardCapacity(hotCapacity), cache_shard.WithTTL(o.hotTTL)),
+		client:       o.client,
+		fetches:      lru.New[string, int](cacheShards * hotCapacity),
+		hotThreshold: o.hotThreshold,
+		hotTTL:       o.hotTTL,
+		virtualNodes: o.virtualNodes,
+		hasher:       hasher.NewXXHash64WithSeed[string](0),
+		latency:      stats.NewHistogram(),
+	}
+}
+
+// SetPeers - sets the base URL of this node and the base URLs of all nodes of the cluster.
+// Every node must get the same list (the order does not matter).
+func (n *Node[V]) SetPeers(self string, peers ...string) {
+	ring := cache_shard.NewRing(n.virtualNodes, n.hasher)
+	urls := make(map[int]string)
+
+	for _, p := range append([]string{self}, peers...) {
+		p = strings.TrimSuffix(p, "/")
+		// The id on the ring comes from the URL, not from the position in the list,
+		// so every node places a peer at the same points, and a new peer moves only its own keys
+		id := int(n.hasher.Hash(p) >> 1)
+		if _, ok := urls[id]; !ok {
+			urls[id] = p
+			ring.Add(id, 1)
+		}
+	}
+
+	n.mu.Lock()
+	n.self = strings.TrimSuffix(self, "/")
+	n.ring = ring
+	n.peers = urls
+	n.mu.Unlock()
+}
+
+// Owner - returns the base URL of the node that owns a key
+func (n *Node[V]) Owner(key string) string {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	if n.ring == nil {
+		return n.self
+	}
+	return n.peers[n.ring.Locate(n.hasher.Hash(key))]
+}
+
+// owns - reports whether this node owns a key (a node without peers owns everything)
+func (n *Node[V]) owns(key string) bool {
+	owner := n.Owner(key)
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return owner == n.self
+}
+
+// Get - returns a value from the local caches, the owning peer or the loader
+func (n *Node[V]) Get(ctx context.Context, key string) (V, error) {
+	var zero V
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	if v, err := n.owned.Get(ctx, key); err == nil {
+		n.ownedHits.Add(1)
+		return v, nil
+	}
+	if v, err := n.hot.Get(ctx, key); err == nil {
+		n.hotHits.Add(1)
+		return v, nil
+	}
+	n.misses.Add(1)
+
+	ch := n.group.DoChan(key, func() (any, error) {
+		return n.fetch(context.WithoutCancel(ctx), key)
+	})
+
+	select {
+	case res := <-ch:
+		if res.Err != nil {
+			return zero, res.Err
+		}
+		v, _ := res.Val.(V) // nil when V is an interface and the loader returned nil
+		return v, nil
+	case <-ctx.Done():
+		return zero, ctx.Err()
+	}
+}
+
+// Set - stores a value in the local cache of the owner role (only this node sees it)
+func (n *Node[V]) Set(ctx context.Context, key string, value V) error {
+	return n.owned.Set(ctx, key, value)
+}
+
+// Delete - removes a key from the local caches of this node (other nodes keep their copies)
+func (n *Node[V]) Delete(ctx context.Context, key string) error {
+	if err := n.hot.Delete(ctx, key); err != nil {
+		return err
+	}
+	return n.owned.Delete(ctx, key)
+}
+
+// Stats - returns the common cache counters (see data_struct/cache/stats): hits of the owned and hot caches,
+// misses of Get, evictions, expirations, size and cost of both caches and the latency of the loader
+func (n *Node[V]) Stats() stats.Stats {
+	owned, hot := n.owned.Stats(), n.hot.Stats()
+	return stats.Stats{
+		Hits:        n.ownedHits.Load() + n.hotHits.Load(),
+		Misses:      n.misses.Load(),
+		Evictions:   owned.Evictions + hot.Evictions,
+		Expirations: owned.Expirations + hot.Expirations,
+		Size:        owned.Size + hot.Size,
+		Cost:        owned.Cost + hot.Cost,
+		LoadErrors:  n.loadErrors.Load(),
+		LoadLatency: n.latency.Snapshot(),
+	}
+}
+
+// NodeStats - returns the peer-to-peer counters of the node
+func (n *Node[V]) NodeStats() NodeStats {
+	return NodeStats{
+		OwnedHits:   n.ownedHits.Load(),
+		HotHits:     n.hotHits.Load(),
+		Loads:       n.loads.Load(),
+		PeerFetches: n.peerFetches.Load(),
+		PeerErrors:  n.peerErrors.Load(),
+		PeerServed:  n.peerServed.Load(),
+	}
+}
+
+// ServeHTTP - serves GET /_peer/<key> for other nodes
+func (n *Node[V]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet || !strings.HasPrefix(r.URL.Path, BasePath) {
+		http.NotFound(w, r)
+		return
+	}
+
+	key, err := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), BasePath))
+	if err != nil || key == "" {
+		http.Error(w, "bad key", http.StatusBadRequest)
+		return
+	}
+
+	n.peerServed.Add(1)
+	w.Header().Set("Content-Type", "application/json")
+
+	// A request from a peer is always served locally, even if this node thinks another one is the owner;
+	// any other request is served like Get
+	get := n.Get
+	if r.Header.Get(forwardedHeader) != "" {
+		get = n.getLocal
+	}
+	value, err := get(r.Context(), key)
+	if err != nil {
+		w.WriteHeader(http.StatusBadGateway) // The loader failed, not this node
+		json.NewEncoder(w).Encode(reply[V]{Error: err.Error()})
+		return
+	}
+
+	json.NewEncoder(w).Encode(reply[V]{Value: value})
+}
+
+// fetch - gets a key from its owner or loads it if this node is the owner (runs once per key at a time)
+func (n *Node[V]) fetch(ctx context.Context, key string) (V, error) {
+	if n.owns(key) {
+		return n.loadOwned(ctx, key)
+	}
+	owner := n.Owner(key)
+
+	value, err := n.fetchFromPeer(ctx, owner, key)
+	switch {
+	case errors.Is(err, ErrOwnerLoad):
+		return value, err // The owner has already tried: the error is shared like the value would be
+	case err != nil:
+		// The owner is down or unreachable: degrade to a local load, but do not claim the key
+		n.peerErrors.Add(1)
+		return n.call(ctx, key)
+	}
+
+	n.peerFetches.Add(1)
+	if n.recordFetch(key) >= n.hotThreshold {
+		n.hot.Set(ctx, key, value)
+	}
+	return value, nil
+}
+
+// getLocal - serves a peer request: the owned cache or a deduplicated load
+func (n *Node[V]) getLocal(ctx context.Context, key string) (V, error) {
+	if v, err := n.owned.Get(ctx, key); err == nil {
+		n.ownedHits.Add(1)
+		return v, nil
+	}
+
+	// Normally this node owns the key and shares the load with its own Get of the key.
+	// If the rings disagree (a rollout of a new peer list), this node's own Get of the key may be forwarded
+	// back to the peer that is waiting here, so the load goes to a separate group and never waits for it
+	group := &n.group
+	if !n.owns(key) {
+		group = &n.forwarded
+	}
+
+	res, err, _ := group.Do(key, func() (any, error) {
+		return n.loadOwned(context.WithoutCancel(ctx), key)
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	v, _ := res.(V)
+	return v, nil
+}
+
+// loadOwned - loads a key owned by this node and keeps it
+func (n *Node[V]) loadOwned(ctx context.Context, key string) (V, error) {
+	// Another request may have loaded the key while this one waited for singleflight
+	if v, err := n.owned.Get(ctx, key); err == nil {
+		return v, nil
+	}
+
+	value, err := n.call(ctx, key)
+	if err != nil {
+		return value, err
+	}
+
+	n.owned.Set(ctx, key, value)
+	return value, nil
+}
+
+// call - calls the loader and records its latency and errors
+func (n *Node[V]) call(ctx context.Context, key string) (V, error) {
+	n.loads.Add(1)
+	start := time.Now()
+	value, err := n.loader(ctx, key)
+
+	n.latency.Observe(time.Since(start))
+	if err != nil {
+		n.loadErrors.Add(1)
+	}
+	return value, err
+}
+
+// fetchFromPeer - GET <owner>/_peer/<key> with a header that forbids forwarding
+func (n *Node[V]) fetchFromPeer(ctx context.Context, owner, key string) (V, error) {
+	var zero V
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, owner+BasePath+url.PathEscape(key), nil)
+	if err != nil {
+		return zero, err
+	}
+	req.Header.Set(forwardedHeader, "1")
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return zero, err
+	}
+	defer resp.Body.Close()
+
+	// Any other status or body (e.g. a 503 page of a proxy in front of the owner) means the owner is unreachable
+	isJSON := strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json")
+	switch {
+	case resp.StatusCode == http.StatusBadGateway && isJSON:
+		var body reply[V]
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil || body.Error == "" {
+			return zero, fmt.Errorf("peer %s: %s", owner, resp.Status)
+		}
+		return zero, fmt.Errorf("%w: %s: %s", ErrOwnerLoad, owner, body.Error)
+	case resp.StatusCode != http.StatusOK:
+		return zero, fmt.Errorf("peer %s: %s", owner, resp.Status)
+	}
+
+	var body reply[V]
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return zero, fmt.Errorf("peer %s: decode: %w", owner, err)
+	}
+	return body.Value, nil
+}
+
+// recordFetch - counts a remote fetch of a key and returns the count
+// (fetches of one key never overlap on a node because of singleflight)
+func (n *Node[V]) recordFetch(key string) int {
+	count, _ := n.fetches.Peek(key)
+	count++
+	n.fetches.Set(context.Background(), key, count)
+	return count
+}
@@ -0,0 +1,346 @@
+package peer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingLoader - a slow loader that counts its calls per key
+type countingLoader struct {
+	mu    sync.Mutex
+	loads map[string]int
+}
+
+func newCountingLoader() *countingLoader {
+	return &countingLoader{loads: make(map[string]int)}
+}
+
+func (l *countingLoader) load(ctx context.Context, key string) (string, error) {
+	l.mu.Lock()
+	l.loads[key]++
+	l.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond) // Keeps concurrent requests overlapping
+	return "value of " + key, nil
+}
+
+func (l *countingLoader) count(key string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.loads[key]
+}
+
+// startCluster - starts n nodes with httptest servers and joins them into one cluster
+func startCluster(t *testing.T, n int, loader Loader[string], opts ...Option) ([]*Node[string], []*httptest.Server) {
+	t.Helper()
+
+	nodes := make([]*Node[string], n)
+	servers := make([]*httptest.Server, n)
+	urls := make([]string, n)
+	for i := range nodes {
+		nodes[i] = New(loader, opts...)
+		servers[i] = httptest.NewServer(nodes[i])
+		urls[i] = servers[i].URL
+		t.Cleanup(servers[i].Close)
+	}
+	for i, node := range nodes {
+		node.SetPeers(urls[i], urls...)
+	}
+	return nodes, servers
+}
+
+func TestNodesAgreeOnOwners(t *testing.T) {
+	nodes, servers := startCluster(t, 3, newCountingLoader().load)
+
+	owned := make(map[string]int)
+	for i := range 3000 {
+		key := fmt.Sprintf("key:%d", i)
+		owner := nodes[0].Owner(key)
+		for j, node := range nodes[1:] {
+			if got := node.Owner(key); got != owner {
+				t.Fatalf("node %d: Owner(%s) = %s, node 0 says %s", j+1, key, got, owner)
+			}
+		}
+		owned[owner]++
+	}
+
+	for _, s := range servers {
+		if owned[s.URL] == 0 {
+			t.Errorf("node %s owns no keys: %v", s.URL, owned)
+		}
+	}
+}
+
+func TestOwnerLoadsEachKeyOnce(t *testing.T) {
+	loader := newCountingLoader()
+	nodes, servers := startCluster(t, 3, loader.load)
+	ctx := context.Background()
+
+	keys := make([]string, 20)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("user:%d", i)
+	}
+
+	var wg sync.WaitGroup
+	for i := range 300 {
+		wg.Add(1)
+		go func(node *Node[string], key string) {
+			defer wg.Done()
+
+			v, err := node.Get(ctx, key)
+			if err != nil || v != "value of "+key {
+				t.Errorf("Get(%s) = %q, %v", key, v, err)
+			}
+		}(nodes[i%len(nodes)], keys[i%len(keys)])
+	}
+	wg.Wait()
+
+	for _, key := range keys {
+		if n := loader.count(key); n != 1 {
+			t.Errorf("%s loaded %d times, want 1", key, n)
+		}
+	}
+
+	// Every key was loaded by its owner
+	for i, node := range nodes {
+		owned := 0
+		for _, key := range keys {
+			if node.Owner(key) == servers[i].URL {
+				owned++
+			}
+		}
+		if got := node.NodeStats().Loads; got != uint64(owned) {
+			t.Errorf("node %d: %d loads, owns %d of the keys", i, got, owned)
+		}
+	}
+}
+
+func TestHotKeyIsReplicated(t *testing.T) {
+	loader := newCountingLoader()
+	nodes, servers := startCluster(t, 2, loader.load, WithHotThreshold(2))
+	ctx := context.Background()
+
+	key := "user:42"
+	reader := nodes[0]
+	if reader.Owner(key) == servers[0].URL {
+		reader = nodes[1]
+	}
+
+	for range 5 {
+		if _, err := reader.Get(ctx, key); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := reader.NodeStats()
+	if s.PeerFetches != 2 || s.HotHits != 3 {
+		t.Errorf("got %d fetches and %d hot hits, want 2 and 3", s.PeerFetches, s.HotHits)
+	}
+	if n := loader.count(key); n != 1 {
+		t.Errorf("loaded %d times, want 1", n)
+	}
+}
+
+func TestFailoverWhenOwnerIsDown(t *testing.T) {
+	loader := newCountingLoader()
+	nodes, servers := startCluster(t, 3, loader.load)
+	ctx := context.Background()
+
+	key := "report:7"
+	owner := -1
+	for i, s := range servers {
+		if s.URL == nodes[0].Owner(key) {
+			owner = i
+		}
+	}
+	servers[owner].Close()
+	other := nodes[(owner+1)%len(nodes)]
+
+	v, err := other.Get(ctx, key)
+	if err != nil || v != "value of "+key {
+		t.Fatalf("Get with the owner down = %q, %v", v, err)
+	}
+	if s := other.NodeStats(); s.PeerErrors != 1 || s.Loads != 1 {
+		t.Errorf("got %d peer errors and %d loads, want 1 and 1", s.PeerErrors, s.Loads)
+	}
+	if n := loader.count(key); n != 1 {
+		t.Errorf("loaded %d times, want 1", n)
+	}
+}
+
+func TestOwnerLoadErrorIsShared(t *testing.T) {
+	var calls atomic.Int64
+	nodes, servers := startCluster(t, 3, func(ctx context.Context, key string) (string, error) {
+		calls.Add(1)
+		time.Sleep(50 * time.Millisecond) // All the requests below arrive while the load is running
+		return "", errors.New("no such user")
+	})
+	ctx := context.Background()
+
+	key := "user:404"
+	var others []*Node[string]
+	for i, node := range nodes {
+		if node.Owner(key) != servers[i].URL {
+			others = append(others, node)
+		}
+	}
+
+	// The owner loads the key once and the error goes to every node that asked, none of them loads it again
+	var wg sync.WaitGroup
+	for i := range 30 {
+		wg.Add(1)
+		go func(node *Node[string]) {
+			defer wg.Done()
+
+			if _, err := node.Get(ctx, key); !errors.Is(err, ErrOwnerLoad) || !strings.Contains(err.Error(), "no such user") {
+				t.Errorf("Get = %v, want ErrOwnerLoad with the message of the owner", err)
+			}
+		}(others[i%len(others)])
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("loaded %d times, want 1", n)
+	}
+	for _, node := range others {
+		if s := node.NodeStats(); s.PeerErrors != 0 || s.Loads != 0 {
+			t.Errorf("got %d peer errors and %d loads, want 0 and 0", s.PeerErrors, s.Loads)
+		}
+	}
+}
+
+func TestProxyErrorIsAnUnreachableOwner(t *testing.T) {
+	// A load balancer in front of the owner answers with its own plain-text page
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "no healthy upstream", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(proxy.Close)
+
+	loader := newCountingLoader()
+	node := New(loader.load)
+	node.SetPeers("http://self", "http://self", proxy.URL)
+
+	key := "key:0"
+	for i := 0; node.Owner(key) != proxy.URL; i++ {
+		key = fmt.Sprintf("key:%d", i)
+	}
+
+	_, err := node.fetchFromPeer(context.Background(), proxy.URL, key)
+	if err == nil || errors.Is(err, ErrOwnerLoad) || !strings.Contains(err.Error(), "503") {
+		t.Errorf("fetchFromPeer = %v, want the 503 status", err)
+	}
+
+	v, err := node.Get(context.Background(), key)
+	if err != nil || v != "value of "+key {
+		t.Fatalf("Get = %q, %v, want a local load", v, err)
+	}
+	if s := node.NodeStats(); s.PeerErrors != 1 || s.Loads != 1 {
+		t.Errorf("got %d peer errors and %d loads, want 1 and 1", s.PeerErrors, s.Loads)
+	}
+}
+
+func TestForwardedRequestIsServedLocally(t *testing.T) {
+	loader := newCountingLoader()
+	node := New(loader.load)
+	server := httptest.NewServer(node)
+	t.Cleanup(server.Close)
+
+	// The other peer is unreachable, and this node thinks it owns some of the keys
+	dead := "http://127.0.0.1:1"
+	node.SetPeers(server.URL, server.URL, dead)
+
+	var key string
+	for i := 0; key == ""; i++ {
+		if k := fmt.Sprintf("key:%d", i); node.Owner(k) == dead {
+			key = k
+		}
+	}
+
+	// A request from a peer is loaded here, never forwarded to the owner by this node's ring
+	req := httptest.NewRequest(http.MethodGet, BasePath+key, nil)
+	req.Header.Set(forwardedHeader, "1")
+	rec := httptest.NewRecorder()
+	node.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	if s := node.NodeStats(); s.PeerErrors != 0 || s.Loads != 1 {
+		t.Errorf("forwarded: %d peer errors and %d loads, want 0 and 1", s.PeerErrors, s.Loads)
+	}
+
+	// A request without the header is served like Get and tries the owner first
+	other := key + ":other"
+	for node.Owner(other) != dead {
+		other += "x"
+	}
+	rec = httptest.NewRecorder()
+	node.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, BasePath+other, nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	if s := node.NodeStats(); s.PeerErrors != 1 {
+		t.Errorf("not forwarded: %d peer errors, want 1", s.PeerErrors)
+	}
+}
+
+func TestStats(t *testing.T) {
+	nodes, servers := startCluster(t, 2, func(ctx context.Context, key string) (string, error) {
+		if strings.HasPrefix(key, "broken") {
+			return "", errors.New("database is down")
+		}
+		return "value of " + key, nil
+	})
+	ctx := context.Background()
+
+	// ownedBy - finds a key with the prefix owned by the i-th node
+	ownedBy := func(i int, prefix string) string {
+		for j := 0; ; j++ {
+			if k := fmt.Sprintf("%s:%d", prefix, j); nodes[0].Owner(k) == servers[i].URL {
+				return k
+			}
+		}
+	}
+
+	node := nodes[0]
+	key := ownedBy(0, "key")
+	for range 3 {
+		node.Get(ctx, key)
+	}
+
+	s := node.Stats()
+	if s.Hits != 2 || s.Misses != 1 || s.Size != 1 {
+		t.Errorf("got %d hits, %d misses, size %d, want 2, 1, 1", s.Hits, s.Misses, s.Size)
+	}
+	if s.LoadLatency.Count != 1 || s.LoadErrors != 0 {
+		t.Errorf("got %d observed loads and %d errors, want 1 and 0", s.LoadLatency.Count, s.LoadErrors)
+	}
+
+	if _, err := nodes[1].Get(ctx, ownedBy(1, "broken")); err == nil {
+		t.Fatal("Get of a broken key returned no error")
+	}
+	if s := nodes[1].Stats(); s.LoadErrors != 1 {
+		t.Errorf("got %d load errors, want 1", s.LoadErrors)
+	}
+}
+
+func TestNilInterfaceValue(t *testing.T) {
+	node := New(func(ctx context.Context, key string) (any, error) {
+		return nil, nil
+	})
+
+	v, err := node.Get(context.Background(), "missing")
+	if v != nil || err != nil {
+		t.Errorf("Get = %v, %v, want nil, nil", v, err)
+	}
+}
@@ -0,0 +1,78 @@
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// sessions - a tiny Snapshotter/Restorer used to show the file helpers
+type sessions struct {
+	records []Record[string, string]
+}
+
+// Snapshot - writes the sessions
+func (s *sessions) Snapshot(w io.Writer) error {
+	return Write(w, KindTTL, s.records)
+}
+
+// Restore - reads the sessions
+func (s *sessions) Restore(r io.Reader) error {
+	records, err := Read[string, string](r, KindTTL)
+	if err != nil {
+		return err
+	}
+	s.records = records
+	return nil
+}
+
+// Example demonstrates the snapshot format and the file helpers.
+// The caches themselves (lru, cache_ttl, cache_shard) have Snapshot and Restore methods, see their examples.
+func Example() {
+	records := []Record[string, int]{
+		{Key: "a", Value: 1},                         // Never expires
+		{Key: "b", Value: 2, Remaining: time.Minute}, // Expires in a minute
+	}
+
+	var buf bytes.Buffer
+	Write(&buf, KindLRU, records)
+	fmt.Printf("snapshot of %d records: %d bytes, magic %q, version %d\n",
+		len(records), buf.Len(), buf.Bytes()[:4], buf.Bytes()[4])
+
+	restored, err := Read[string, int](bytes.NewReader(buf.Bytes()), KindLRU)
+	fmt.Printf("restored: %+v, err: %v\n", restored, err)
+
+	// A flipped bit is detected by the checksum
+	corrupted := bytes.Clone(buf.Bytes())
+	corrupted[len(corrupted)/2] ^= 1
+	_, err = Read[string, int](bytes.NewReader(corrupted), KindLRU)
+	fmt.Printf("corrupted: %v (ErrChecksum: %t)\n", err, errors.Is(err, ErrChecksum))
+
+	// A snapshot of another cache type is rejected
+	_, err = Read[string, int](bytes.NewReader(buf.Bytes()), KindShard)
+	fmt.Printf("wrong kind: %v\n", err)
+
+	// Periodic snapshots to a file with atomic rename
+	dir, _ := os.MkdirTemp("", "snapshot-example")
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sessions.snap")
+
+	live := &sessions{records: []Record[string, string]{{Key: "session:1", Value: "alice", Remaining: time.Hour}}}
+	stop := Every(path, 10*time.Millisecond, live, func(err error) {
+		fmt.Println("snapshot failed:", err)
+	})
+	time.Sleep(25 * time.Millisecond)
+	stop() // Writes the last snapshot before returning
+
+	// After a "restart" the new process restores the file
+	warm := &sessions{}
+	err = ReadFile(path, warm)
+	fmt.Printf("warm start: %v, err: %v\n", warm.records, err)
+
+	err = ReadFile(filepath.Join(dir, "missing.snap"), warm)
+	fmt.Printf("no snapshot yet: %t\n", errors.Is(err, os.ErrNotExist))
+}
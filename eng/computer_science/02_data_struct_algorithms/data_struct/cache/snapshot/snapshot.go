@@ -0,0 +1,265 @@
+/*
+Cache Snapshot
+
+What is it?
+A snapshot is the content of a cache written to a file (or any io.Writer) in a binary format,
+so a restarted service can restore it and start warm.
+
+Why is it needed?
+- An in-memory cache is empty after every deploy or crash (a cold start).
+- Until it warms up, every request goes to the database: the load spikes exactly when the service is restarting.
+- Restoring a recent snapshot avoids the cold start.
+
+What's the point?
+- The format is versioned: a new version of the code can refuse (or convert) an old snapshot instead of misreading it.
+- The format is checksummed (CRC-32C): a truncated or corrupted file is rejected as a whole.
+- Remaining lifetimes are stored instead of absolute times, so an entry does not get a longer life
+  because the service was down (the clock of the new process starts from the restore).
+- Records are written from the least to the most recently used, so restoring them in order rebuilds the LRU order.
+- WriteFile writes a temporary file and renames it, so a crash in the middle never leaves a half-written snapshot.
+
+Format (all integers are big-endian):
+
+	magic    [4]byte  "CSNP"
+	version  uint8    Version
+	kind     uint8    the type of the cache (KindLRU, KindTTL, KindShard, KindStore)
+	length   uint64   length of the body
+	body     []byte   a gob stream: uint64 count, then count Record[K, V] values
+	checksum uint32   CRC-32C of everything above
+
+Keys and values are encoded with encoding/gob, so they must be gob-encodable (exported struct fields).
+
+Examples of using Cache Snapshot:
+See the example.go file.
+*/
+
+package snapshot
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+)
+
+// Version - the current version of the snapshot format
+const Version = 1
+
+// magic - the first bytes of every snapshot
+const magic = "CSNP"
+
+// headerSize - magic + version + kind + body length
+const headerSize = len(magic) + 1 + 1 + 8
+
+// Errors of reading a snapshot
+const (
+	ErrFormat   = cache.Error("snapshot: not a cache snapshot")
+	ErrVersion  = cache.Error("snapshot: unsupported version")
+	ErrKind     = cache.Error("snapshot: written by another type of cache")
+	ErrChecksum = cache.Error("snapshot: checksum mismatch")
+)
+
+// defaultInterval - the interval of Every when the given one is not positive
+const defaultInterval = time.Minute
+
+// castagnoli - the CRC-32C table (hardware accelerated on most CPUs)
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// Kind - the type of the cache that wrote a snapshot
+type Kind uint8
+
+const (
+	KindLRU   Kind = iota + 1 // lru.LRU
+	KindTTL                   // cache_ttl.Cache
+	KindShard                 // cache_shard.Cache
+	KindStore                 // store.File (a backing store, not a cache)
+)
+
+// Record - one cache entry in a snapshot
+type Record[K comparable, V any] struct {
+	Key       K
+	Value     V
+	Remaining time.Duration // Time left until the entry expires, 0 - never expires
+	TTL       time.Duration // The lifetime the entry was set with (needed for sliding expiration), 0 - unknown
+}
+
+// Write - writes records as a snapshot of the given kind
+func Write[K comparable, V any](w io.Writer, kind Kind, records []Record[K, V]) error {
+	var body bytes.Buffer
+	enc := gob.NewEncoder(&body)
+
+	if err := enc.Encode(uint64(len(records))); err != nil {
+		return fmt.Errorf("snapshot: encode: %w", err)
+	}
+	for i := range records {
+		if err := enc.Encode(&records[i]); err != nil {
+			return fmt.Errorf("snapshot: encode: %w", err)
+		}
+	}
+
+	header := make([]byte, 0, headerSize)
+	header = append(header, magic...)
+	header = append(header, Version, byte(kind))
+	header = binary.BigEndian.AppendUint64(header, uint64(body.Len()))
+
+	sum := crc32.Update(crc32.Checksum(header, castagnoli), castagnoli, body.Bytes())
+
+	for _, part := range [][]byte{header, body.Bytes(), binary.BigEndian.AppendUint32(nil, sum)} {
+		if _, err := w.Write(part); err != nil {
+			return fmt.Errorf("snapshot: write: %w", err)
+		}
+	}
+	return nil
+}
+
+// Read - reads a snapshot of the given kind and verifies its version and checksum
+func Read[K comparable, V any](r io.Reader, kind Kind) ([]Record[K, V], error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrFormat, err)
+	}
+
+	switch {
+	case string(header[:len(magic)]) != magic:
+		return nil, ErrFormat
+	case header[len(magic)] != Version:
+		return nil, fmt.Errorf("%w: %d", ErrVersion, header[len(magic)])
+	case Kind(header[len(magic)+1]) != kind:
+		return nil, ErrKind
+	}
+
+	// CopyN grows the buffer as data arrives, so a corrupted length cannot allocate gigabytes up front
+	length := binary.BigEndian.Uint64(header[len(magic)+2:])
+	if length > math.MaxInt64-4 {
+		return nil, ErrFormat // int64(length)+4 would overflow
+	}
+	var buf bytes.Buffer
+	if _, err := io.CopyN(&buf, r, int64(length)+4); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrChecksum, err) // Truncated
+	}
+	if uint64(buf.Len()) != length+4 {
+		return nil, ErrChecksum
+	}
+
+	body, tail := buf.Bytes()[:length], buf.Bytes()[length:]
+	sum := crc32.Update(crc32.Checksum(header, castagnoli), castagnoli, body)
+	if binary.BigEndian.Uint32(tail) != sum {
+		return nil, ErrChecksum
+	}
+
+	dec := gob.NewDecoder(bytes.NewReader(body))
+
+	var count uint64
+	if err := dec.Decode(&count); err != nil {
+		return nil, fmt.Errorf("snapshot: decode: %w", err)
+	}
+
+	// Every record takes at least one byte of the body, so a corrupted count cannot allocate more than the body
+	records := make([]Record[K, V], 0, min(count, uint64(len(body))))
+	for range count {
+		var rec Record[K, V]
+		if err := dec.Decode(&rec); err != nil {
+			return nil, fmt.Errorf("snapshot: decode: %w", err)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// Snapshotter - a cache that can write its content
+type Snapshotter interface {
+	Snapshot(w io.Writer) error
+}
+
+// Restorer - a cache that can replace its content with a snapshot
+type Restorer interface {
+	Restore(r io.Reader) error
+}
+
+// WriteFile - writes a snapshot to a file atomically: a temporary file in the same directory, fsync, rename
+func WriteFile(path string, s Snapshotter) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("snapshot: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op after a successful rename
+
+	if err := s.Snapshot(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("snapshot: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("snapshot: %w", err)
+	}
+
+	// Rename is atomic: readers see either the old snapshot or the new one, never a mix
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("snapshot: %w", err)
+	}
+	return nil
+}
+
+// ReadFile - restores a cache from a snapshot file (os.ErrNotExist if there is no snapshot yet)
+func ReadFile(path string, r Restorer) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("snapshot: %w", err)
+	}
+	defer f.Close()
+
+	return r.Restore(f)
+}
+
+// Every - writes a snapshot to a file every interval (1 minute if interval is not positive) until stop is called.
+// Errors are passed to onError (may be nil); stop writes one last snapshot and waits for the writer goroutine,
+// later calls of stop do nothing.
+func Every(path string, interval time.Duration, s Snapshotter, onError func(error)) (stop func()) {
+	if interval <= 0 {
+		interval = defaultInterval // time.NewTicker panics, and in the goroutine nobody could recover
+	}
+
+	var once sync.Once
+	done := make(chan struct{})
+	finished := make(chan struct{})
+
+	save := func() {
+		if err := WriteFile(path, s); err != nil && onError != nil {
+			onError(err)
+		}
+	}
+
+	go func() {
+		defer close(finished)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				save()
+			case <-done:
+				save() // The final snapshot on shutdown
+				return
+			}
+		}
+	}()
+
+	return func() {
+		once.Do(func() { close(done) })
+		<-finished
+	}
+}
@@ -0,0 +1,61 @@
+package stats
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"time"
+)
+
+// counting - a toy cache that reports its counters (real caches return Stats from their own Stats method)
+type counting struct {
+	stats Stats
+}
+
+// Stats - returns the counters
+func (c *counting) Stats() Stats {
+	return c.stats
+}
+
+// Example demonstrates the use of a latency histogram and a stats registry
+func Example() {
+	// A latency histogram: Observe is one atomic increment, quantiles come from the buckets
+	latency := NewHistogram()
+	for i := 1; i <= 100; i++ {
+		latency.Observe(time.Duration(i) * 200 * time.Microsecond) // 0.2ms .. 20ms
+	}
+
+	snapshot := latency.Snapshot()
+	fmt.Printf("loads: %d, mean %v, p50 <= %v, p99 <= %v\n",
+		snapshot.Count, snapshot.Mean(), snapshot.Quantile(0.5), snapshot.Quantile(0.99))
+
+	// Two caches in one registry
+	registry := NewRegistry()
+	registry.Register("sessions", &counting{stats: Stats{Hits: 90, Misses: 10, Evictions: 3, Size: 42}})
+	registry.Register("users", &counting{stats: Stats{Hits: 5, Misses: 5, Size: 5, LoadErrors: 1, LoadLatency: snapshot}})
+
+	// The registry is an http.Handler: mount it at /metrics
+	server := httptest.NewServer(registry)
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/metrics")
+	if err != nil {
+		fmt.Println("scrape:", err)
+		return
+	}
+	defer resp.Body.Close()
+	fmt.Println("Content-Type:", resp.Header.Get("Content-Type"))
+
+	// The same text without HTTP, only the families of the hits and the histogram count
+	var b strings.Builder
+	registry.WriteText(&b)
+	for _, line := range strings.Split(b.String(), "\n") {
+		if strings.Contains(line, "cache_hits_total") || strings.Contains(line, "_count") {
+			fmt.Println(line)
+		}
+	}
+
+	// The whole exposition
+	registry.WriteText(os.Stdout)
+}
@@ -0,0 +1,135 @@
+package stats
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Registry - a named set of caches exposed in the Prometheus text format; it is an http.Handler
+type Registry struct {
+	mu      sync.RWMutex
+	sources map[string]Source
+}
+
+// NewRegistry - creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{sources: make(map[string]Source)}
+}
+
+// Register - adds a cache under a name (the value of the "cache" label); a repeated name replaces the cache
+func (r *Registry) Register(name string, s Source) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.sources[name] = s
+}
+
+// Unregister - removes a cache
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.sources, name)
+}
+
+// ServeHTTP - writes the metrics of all registered caches
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	r.WriteText(w)
+}
+
+// sample - the statistics of one named cache
+type sample struct {
+	name  string
+	stats Stats
+}
+
+// metric - one simple metric family: a counter or a gauge taken from Stats
+type metric struct {
+	name, kind, help string
+	value            func(Stats) string
+}
+
+// metrics - the simple families in the order of exposition
+var metrics = []metric{
+	{"cache_hits_total", "counter", "Lookups that found a live key.", func(s Stats) string { return uintText(s.Hits) }},
+	{"cache_misses_total", "counter", "Lookups that found nothing.", func(s Stats) string { return uintText(s.Misses) }},
+	{"cache_evictions_total", "counter", "Entries dropped because of the capacity or the cost budget.", func(s Stats) string { return uintText(s.Evictions) }},
+	{"cache_expirations_total", "counter", "Entries dropped because their lifetime was over.", func(s Stats) string { return uintText(s.Expirations) }},
+	{"cache_size", "gauge", "Entries stored now.", func(s Stats) string { return strconv.Itoa(s.Size) }},
+	{"cache_cost", "gauge", "Total cost of the stored values.", func(s Stats) string { return strconv.FormatInt(s.Cost, 10) }},
+	{"cache_load_errors_total", "counter", "Loads that failed.", func(s Stats) string { return uintText(s.LoadErrors) }},
+}
+
+// WriteText - writes the metrics of all registered caches in the Prometheus text format (version 0.0.4)
+func (r *Registry) WriteText(w io.Writer) error {
+	// The statistics are collected first, so the lock is not held while writing to a slow client
+	r.mu.RLock()
+	samples := make([]sample, 0, len(r.sources))
+	for name, s := range r.sources {
+		samples = append(samples, sample{name: name, stats: s.Stats()})
+	}
+	r.mu.RUnlock()
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i].name < samples[j].name })
+
+	b := bufio.NewWriter(w)
+
+	for _, m := range metrics {
+		writeHeader(b, m.name, m.kind, m.help)
+		for _, s := range samples {
+			b.WriteString(m.name + `{cache="` + escape(s.name) + `"} ` + m.value(s.stats) + "\n")
+		}
+	}
+
+	// The histogram is written only for caches that have one (the ones that load values)
+	const hist = "cache_load_duration_seconds"
+	header := false
+	for _, s := range samples {
+		h := s.stats.LoadLatency
+		if len(h.Bounds) == 0 {
+			continue
+		}
+		if !header {
+			writeHeader(b, hist, "histogram", "Durations of the loads.")
+			header = true
+		}
+
+		label := `cache="` + escape(s.name) + `"`
+		var cumulative uint64
+		for i, bound := range h.Bounds {
+			cumulative += h.Counts[i]
+			le := strconv.FormatFloat(bound.Seconds(), 'g', -1, 64)
+			b.WriteString(hist + "_bucket{" + label + `,le="` + le + `"} ` + uintText(cumulative) + "\n")
+		}
+		b.WriteString(hist + "_bucket{" + label + `,le="+Inf"} ` + uintText(h.Count) + "\n")
+		b.WriteString(hist + "_sum{" + label + "} " + strconv.FormatFloat(h.Sum.Seconds(), 'g', -1, 64) + "\n")
+		b.WriteString(hist + "_count{" + label + "} " + uintText(h.Count) + "\n")
+	}
+
+	return b.Flush()
+}
+
+// writeHeader - writes the HELP and TYPE lines of a metric family
+func writeHeader(b *bufio.Writer, name, kind, help string) {
+	b.WriteString("# HELP " + name + " " + help + "\n")
+	b.WriteString("# TYPE " + name + " " + kind + "\n")
+}
+
+// labelEscaper - escapes a label value: backslash, double quote and line feed
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escape - returns a label value safe for the text format
+func escape(s string) string {
+	return labelEscaper.Replace(s)
+}
+
+// uintText - formats a counter
+func uintText(n uint64) string {
+	return strconv.FormatUint(n, 10)
+}
@@ -0,0 +1,160 @@
+/*
+Cache Statistics
+
+What is it?
+A common set of counters that every cache in this repository reports (Stats), a lock-free latency histogram
+for loads (Histogram) and a Registry that exposes the counters of many caches in the Prometheus text format.
+
+Why is it needed?
+- A cache without metrics is a black box: it is impossible to tell whether it helps at all (hit ratio),
+  whether it is too small (evictions), whether the TTL is too short (expirations) or the backend is slow (load latency).
+- The counters are the same for every policy, so the caches can be compared and monitored by the same dashboards.
+- The exposition format is plain text, so no client library (and no external dependency) is needed:
+  Prometheus, VictoriaMetrics or curl can read it.
+
+What's the point?
+- Stats is a point-in-time snapshot: the cache counts under its own lock (or with atomics) and copies the counters on request.
+- Counters only grow (hits, misses, evictions, expirations, load errors); Size and Cost are gauges.
+- Histogram keeps a counter per latency bucket: Observe is one atomic increment, quantiles are estimated from the buckets.
+- Registry is an http.Handler: mount it at /metrics.
+
+Metrics (every sample has the label cache="<name>"):
+- cache_hits_total, cache_misses_total, cache_evictions_total, cache_expirations_total — counters.
+- cache_size (entries), cache_cost (total cost of the values) — gauges.
+- cache_load_errors_total — counter; cache_load_duration_seconds — histogram (only for caches that load values).
+
+### Complexity
+
+| Operation | Time Complexity (O) |
+|:---|:---:|
+| Histogram.Observe | O(log b) |
+| Registry.WriteText | O(c * b) |
+
+*b — the number of buckets, c — the number of registered caches.
+
+Examples of using the statistics:
+See the example.go file.
+*/
+
+package stats
+
+import (
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// Stats - a snapshot of the counters of a cache
+type Stats struct {
+	Hits        uint64            // Lookups that found a live key
+	Misses      uint64            // Lookups that found nothing
+	Evictions   uint64            // Entries dropped because of the capacity or the cost budget
+	Expirations uint64            // Entries dropped because their lifetime was over
+	Size        int               // Entries stored now
+	Cost        int64             // Total cost of the stored values (caches with a cost budget)
+	LoadErrors  uint64            // Loads that failed (caches that load values)
+	LoadLatency HistogramSnapshot // Durations of the loads (caches that load values)
+}
+
+// HitRatio - returns the share of lookups that found the key
+func (s Stats) HitRatio() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// Source - anything that reports cache statistics
+type Source interface {
+	Stats() Stats
+}
+
+// DefaultBuckets - upper bounds of the latency buckets: from 0.5ms for an in-memory source to 10s for a slow backend
+var DefaultBuckets = []time.Duration{
+	500 * time.Microsecond, time.Millisecond, 2500 * time.Microsecond, 5 * time.Millisecond,
+	10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond, 100 * time.Millisecond,
+	250 * time.Millisecond, 500 * time.Millisecond, time.Second, 2500 * time.Millisecond,
+	5 * time.Second, 10 * time.Second,
+}
+
+// Histogram - a latency histogram with fixed buckets, safe for concurrent use without locks
+type Histogram struct {
+	bounds []time.Duration // Upper bounds, ascending
+	counts []atomic.Uint64 // One per bound plus the last one for +Inf
+	sum    atomic.Int64    // Total of the observed durations in nanoseconds
+	count  atomic.Uint64
+}
+
+// NewHistogram - creates a histogram with the given upper bounds (DefaultBuckets if none are given)
+func NewHistogram(bounds ...time.Duration) *Histogram {
+	if len(bounds) == 0 {
+		bounds = DefaultBuckets
+	}
+
+	bounds = append([]time.Duration(nil), bounds...)
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+
+	return &Histogram{
+		bounds: bounds,
+		counts: make([]atomic.Uint64, len(bounds)+1),
+	}
+}
+
+// Observe - records one duration
+func (h *Histogram) Observe(d time.Duration) {
+	// The first bucket whose upper bound is not less than d (le - "less or equal")
+	i := sort.Search(len(h.bounds), func(i int) bool { return h.bounds[i] >= d })
+
+	h.counts[i].Add(1)
+	h.sum.Add(int64(d))
+	h.count.Add(1)
+}
+
+// Snapshot - copies the current state of the histogram
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	s := HistogramSnapshot{
+		Bounds: h.bounds,
+		Counts: make([]uint64, len(h.counts)),
+	}
+	for i := range h.counts {
+		s.Counts[i] = h.counts[i].Load()
+		s.Count += s.Counts[i]
+	}
+	s.Sum = time.Duration(h.sum.Load())
+
+	return s
+}
+
+// HistogramSnapshot - a copy of a histogram
+type HistogramSnapshot struct {
+	Bounds []time.Duration // Upper bounds of the buckets
+	Counts []uint64        // Observations per bucket (not cumulative); the last one is above all bounds
+	Count  uint64          // Number of observations
+	Sum    time.Duration   // Total of the observed durations
+}
+
+// Mean - returns the average observed duration
+func (s HistogramSnapshot) Mean() time.Duration {
+	if s.Count == 0 {
+		return 0
+	}
+	return s.Sum / time.Duration(s.Count)
+}
+
+// Quantile - estimates the q-quantile (0..1) as the upper bound of the bucket that contains it.
+// For observations above the last bound it returns the last bound.
+func (s HistogramSnapshot) Quantile(q float64) time.Duration {
+	if s.Count == 0 || len(s.Bounds) == 0 {
+		return 0
+	}
+
+	rank := uint64(q * float64(s.Count))
+	var seen uint64
+	for i, bound := range s.Bounds {
+		seen += s.Counts[i]
+		if seen > rank {
+			return bound
+		}
+	}
+	return s.Bounds[len(s.Bounds)-1]
+}
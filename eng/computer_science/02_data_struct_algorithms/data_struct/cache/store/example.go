@@ -0,0 +1,108 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/LRU"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/cache_ttl"
+)
+
+// Example demonstrates the use of write-through and write-behind caches
+func Example() {
+	ctx := context.Background()
+
+	// 1. Write-through over an LRU cache of 2 entries and an in-memory store
+	db := NewMemory[string, int]()
+	through := NewWriteThrough[string, int](lru.New[string, int](2), db)
+
+	for i, key := range []string{"a", "b", "c"} {
+		through.Set(ctx, key, i) // Every Set is in the store when it returns
+	}
+	fmt.Printf("write-through: %d keys in the store after 3 Sets\n", db.Len())
+
+	v, err := through.Get(ctx, "a") // Evicted from the cache, read through from the store
+	fmt.Printf("Get(a) after eviction: %d, err %v\n", v, err)
+
+	db.FailNext(1, errors.New("db is down"))
+	err = through.Set(ctx, "d", 4)
+	_, getErr := through.Get(ctx, "d")
+	fmt.Printf("Set(d) with the store down: %v, Get(d): %v\n", err, getErr)
+
+	// 2. Write-behind over a TTL cache and a file store: 1000 updates of 10 counters become one file write
+	dir, err := os.MkdirTemp("", "store-example")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "counters.snap")
+
+	file, err := OpenFile[string, int](path)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	counters := cache_ttl.New[string, int](time.Hour)
+	defer counters.Stop()
+
+	behind := NewWriteBehind[string, int](counters, file, WithFlushInterval(time.Hour))
+	for i := 0; i < 1000; i++ {
+		behind.Set(ctx, "counter:"+strconv.Itoa(i%10), i)
+	}
+	fmt.Printf("write-behind: %d dirty keys after 1000 Sets, %d keys in the file\n", behind.Pending(), file.Len())
+
+	err = behind.Flush(ctx)
+	fmt.Printf("after Flush: %d dirty keys, %d keys in the file, err %v\n", behind.Pending(), file.Len(), err)
+
+	behind.Delete(ctx, "counter:9")
+	behind.Close(ctx) // The last changes are written on close
+
+	reopened, err := OpenFile[string, int](path)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	v, _ = reopened.Get(ctx, "counter:3")
+	fmt.Printf("reopened file: %d keys, counter:3 = %d\n", reopened.Len(), v)
+
+	// 3. Retries: the store fails twice, the third attempt of the batch succeeds
+	flaky := NewMemory[string, string]()
+	retried := NewWriteBehind[string, string](lru.New[string, string](100), flaky,
+		WithFlushInterval(time.Hour), WithRetry(3, time.Millisecond))
+
+	retried.Set(ctx, "user:1", "alice")
+	flaky.FailNext(2, errors.New("timeout"))
+	err = retried.Flush(ctx)
+	fmt.Printf("flush after 2 failures: err %v, %d keys in the store\n", err, flaky.Len())
+	retried.Close(ctx)
+
+	// 4. Back-pressure: the store is down, at most 5 keys may wait, the 6th Set blocks until its deadline
+	down := NewMemory[string, int]()
+	down.FailNext(1_000_000, errors.New("db is down"))
+
+	var failed int
+	pressured := NewWriteBehind[string, int](lru.New[string, int](100), down,
+		WithFlushInterval(10*time.Millisecond), WithBatchSize(5), WithMaxPending(5), WithRetry(1, 0),
+		WithOnFlushError(func(writes []Write[string, int], err error) { failed++ }))
+
+	for i := 0; i < 5; i++ {
+		pressured.Set(ctx, "k"+strconv.Itoa(i), i)
+	}
+
+	timeout, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	err = pressured.Set(timeout, "k5", 5)
+	fmt.Printf("6th Set with the store down: %v, dirty keys kept: %d\n", err, pressured.Pending())
+
+	// The store recovers: the kept entries are written by the next flush
+	down.FailNext(0, nil)
+	err = pressured.Close(ctx)
+	fmt.Printf("after recovery: %d keys in the store, failed flushes reported: %t, err %v\n", down.Len(), failed > 0, err)
+}
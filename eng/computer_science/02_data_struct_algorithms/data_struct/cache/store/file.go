@@ -0,0 +1,141 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/snapshot"
+)
+
+// File - a store that keeps all the data in memory and persists it to one file after every write.
+// The file is a snapshot (see data_struct/cache/snapshot): a crash during a write leaves the previous version,
+// a damaged file is detected by the checksum. Every write rewrites the whole file, so it suits small data sets;
+// a write-behind cache in front of it turns many writes into one.
+type File[K comparable, V any] struct {
+	mu   sync.RWMutex
+	path string
+	data map[K]V
+}
+
+var _ Store[string, int] = (*File[string, int])(nil)
+var _ BatchWriter[string, int] = (*File[string, int])(nil)
+
+// OpenFile - opens a file store, loading the file if it exists. Keys and values must be gob-encodable.
+func OpenFile[K comparable, V any](path string) (*File[K, V], error) {
+	f := &File[K, V]{path: path, data: make(map[K]V)}
+
+	err := snapshot.ReadFile(path, persisted[K, V]{f})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Get - returns the stored value or cache.ErrNotFound
+func (f *File[K, V]) Get(ctx context.Context, key K) (V, error) {
+	var zero V
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	v, ok := f.data[key]
+	if !ok {
+		return zero, cache.ErrNotFound
+	}
+	return v, nil
+}
+
+// Put - stores a value and persists the file
+func (f *File[K, V]) Put(ctx context.Context, key K, value V) error {
+	return f.WriteBatch(ctx, []Write[K, V]{{Key: key, Value: value}})
+}
+
+// Delete - removes a key and persists the file
+func (f *File[K, V]) Delete(ctx context.Context, key K) error {
+	return f.WriteBatch(ctx, []Write[K, V]{{Key: key, Deleted: true}})
+}
+
+// WriteBatch - applies the writes and persists the file once; if the file cannot be written,
+// the data in memory is rolled back, so the store never reports what is not on disk
+func (f *File[K, V]) WriteBatch(ctx context.Context, writes []Write[K, V]) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	// The previous values of the touched keys, to undo the batch on a failure
+	type previous struct {
+		value V
+		ok    bool
+	}
+	undo := make(map[K]previous, len(writes))
+
+	for _, w := range writes {
+		if _, seen := undo[w.Key]; !seen {
+			v, ok := f.data[w.Key]
+			undo[w.Key] = previous{value: v, ok: ok}
+		}
+		if w.Deleted {
+			delete(f.data, w.Key)
+		} else {
+			f.data[w.Key] = w.Value
+		}
+	}
+
+	if err := snapshot.WriteFile(f.path, persisted[K, V]{f}); err != nil {
+		for key, p := range undo {
+			if p.ok {
+				f.data[key] = p.value
+			} else {
+				delete(f.data, key)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// Len - returns the number of stored keys
+func (f *File[K, V]) Len() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return len(f.data)
+}
+
+// persisted - the snapshot.Snapshotter and snapshot.Restorer of a file store, unexported because it reads
+// the data without the lock: it is used only under f.mu or before the store is shared
+type persisted[K comparable, V any] struct {
+	f *File[K, V]
+}
+
+// Snapshot - writes all the keys
+func (p persisted[K, V]) Snapshot(w io.Writer) error {
+	records := make([]snapshot.Record[K, V], 0, len(p.f.data))
+	for key, value := range p.f.data {
+		records = append(records, snapshot.Record[K, V]{Key: key, Value: value})
+	}
+	return snapshot.Write(w, snapshot.KindStore, records)
+}
+
+// Restore - loads the keys
+func (p persisted[K, V]) Restore(r io.Reader) error {
+	records, err := snapshot.Read[K, V](r, snapshot.KindStore)
+	if err != nil {
+		return err
+	}
+
+	for _, rec := range records {
+		p.f.data[rec.Key] = rec.Value
+	}
+	return nil
+}
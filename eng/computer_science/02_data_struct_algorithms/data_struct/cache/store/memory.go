@@ -0,0 +1,112 @@
+package store
+
+import (
+	"context"
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+)
+
+// ErrInjected - the error of the writes failed by FailNext when no error is given
+const ErrInjected = cache.Error("store: injected failure")
+
+// Memory - an in-memory store for tests: it counts the calls and can fail on demand
+type Memory[K comparable, V any] struct {
+	mu       sync.Mutex
+	data     map[K]V
+	failures int   // Number of the next writes that fail
+	failErr  error // The error of the failing writes
+	gets     int
+	writes   int // Successful Put, Delete and batches
+}
+
+var _ Store[string, int] = (*Memory[string, int])(nil)
+var _ BatchWriter[string, int] = (*Memory[string, int])(nil)
+
+// NewMemory - creates an empty in-memory store
+func NewMemory[K comparable, V any]() *Memory[K, V] {
+	return &Memory[K, V]{data: make(map[K]V)}
+}
+
+// Get - returns the stored value or cache.ErrNotFound
+func (m *Memory[K, V]) Get(ctx context.Context, key K) (V, error) {
+	var zero V
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.gets++
+	v, ok := m.data[key]
+	if !ok {
+		return zero, cache.ErrNotFound
+	}
+	return v, nil
+}
+
+// Put - stores a value
+func (m *Memory[K, V]) Put(ctx context.Context, key K, value V) error {
+	return m.WriteBatch(ctx, []Write[K, V]{{Key: key, Value: value}})
+}
+
+// Delete - removes a key
+func (m *Memory[K, V]) Delete(ctx context.Context, key K) error {
+	return m.WriteBatch(ctx, []Write[K, V]{{Key: key, Deleted: true}})
+}
+
+// WriteBatch - applies all writes or none of them
+func (m *Memory[K, V]) WriteBatch(ctx context.Context, writes []Write[K, V]) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.failures > 0 {
+		m.failures--
+		return m.failErr
+	}
+
+	m.writes++
+	for _, w := range writes {
+		if w.Deleted {
+			delete(m.data, w.Key)
+		} else {
+			m.data[w.Key] = w.Value
+		}
+	}
+	return nil
+}
+
+// FailNext - makes the next n writes (Put, Delete or a batch) fail with err (ErrInjected if err is nil:
+// a failed write must never look like a success)
+func (m *Memory[K, V]) FailNext(n int, err error) {
+	if err == nil {
+		err = ErrInjected
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.failures = n
+	m.failErr = err
+}
+
+// Len - returns the number of stored keys
+func (m *Memory[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return len(m.data)
+}
+
+// Calls - returns the number of Get calls and of successful writes (a batch counts as one)
+func (m *Memory[K, V]) Calls() (gets, writes int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.gets, m.writes
+}
@@ -0,0 +1,95 @@
+/*
+Backing Store, Write-Through and Write-Behind Caching
+
+What is it?
+A cache usually sits in front of a slower store that holds all the data (a database, a file, a remote service).
+Store is the contract of such a store, and two decorators make a cache keep it up to date on writes:
+- WriteThrough — every Set and Delete goes to the store first and only then to the cache;
+- WriteBehind — Set and Delete change the cache at once, and the store is updated later in batches.
+
+Why is it needed?
+- With a plain cache the application writes to two places and has to keep them consistent itself.
+- Write-through is simple and safe: after Set returns, the data is in the store. But every write waits for the store.
+- Write-behind makes writes as fast as the cache and turns many small writes into a few batches
+  (100 updates of a hot counter become one write). The price: the last writes are lost if the process crashes
+  before a flush.
+
+What's the point?
+- Reads are read-through in both decorators: a miss is loaded from the store and cached.
+- WriteBehind keeps the dirty entries in a pending map (later writes of a key replace the earlier ones)
+  and flushes them every WithFlushInterval or as soon as WithBatchSize keys are dirty.
+- A failed flush is retried with exponential backoff (WithRetry); if it still fails, the entries go back
+  to the pending map (unless they were written again meanwhile) and OnFlushError is called.
+- Back-pressure: at most WithMaxPending dirty keys are kept. When the store cannot keep up, Set blocks
+  until a flush frees space (or its context is canceled) instead of growing the memory without a bound.
+- Until a dirty entry reaches the store, Get finds it in the pending map even if the cache has evicted it.
+- A store that implements BatchWriter gets a whole batch in one call (one transaction, one file write).
+
+Implementations:
+- Memory — an in-memory fake with failure injection, for tests and examples.
+- File — a reference store that keeps the data in memory and persists it to one file
+  in the snapshot format (data_struct/cache/snapshot): checksummed and replaced atomically.
+
+The decorators accept any cache.Cache (lru.LRU, cache_ttl.Cache, ...) and implement cache.Cache themselves.
+
+### Complexity
+
+| Operation | Write-through | Write-behind |
+|:---|:---:|:---:|
+| Get (hit) | O(cache) | O(cache) |
+| Set / Delete | O(cache) + one store write | O(cache) + O(1), amortized 1/batch of a store write |
+
+Examples of using the write-through and write-behind caches:
+See the example.go file.
+*/
+
+package store
+
+import (
+	"context"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+)
+
+// ErrClosed - the write-behind cache was closed
+const ErrClosed = cache.Error("store: write-behind cache is closed")
+
+// Store - the source of truth behind a cache.
+// Get returns cache.ErrNotFound for a missing key; Put and Delete must be idempotent, because they may be retried.
+type Store[K comparable, V any] interface {
+	Get(ctx context.Context, key K) (V, error)
+	Put(ctx context.Context, key K, value V) error
+	Delete(ctx context.Context, key K) error
+}
+
+// Write - one pending change of a key
+type Write[K comparable, V any] struct {
+	Key     K
+	Value   V
+	Deleted bool // The key was deleted, Value is empty
+}
+
+// BatchWriter - a store that applies many writes at once
+type BatchWriter[K comparable, V any] interface {
+	WriteBatch(ctx context.Context, writes []Write[K, V]) error
+}
+
+// apply - writes a batch with WriteBatch if the store supports it, otherwise one write at a time
+func apply[K comparable, V any](ctx context.Context, s Store[K, V], writes []Write[K, V]) error {
+	if b, ok := s.(BatchWriter[K, V]); ok {
+		return b.WriteBatch(ctx, writes)
+	}
+
+	for _, w := range writes {
+		var err error
+		if w.Deleted {
+			err = s.Delete(ctx, w.Key)
+		} else {
+			err = s.Put(ctx, w.Key, w.Value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
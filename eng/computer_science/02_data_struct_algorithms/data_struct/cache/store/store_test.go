@@ -0,0 +1,294 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/LRU"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+)
+
+// gated - a Memory store whose writes and reads can be held and observed by a test
+type gated struct {
+	*Memory[string, int]
+	entered chan []Write[string, int] // Receives every batch before it is applied
+	release chan struct{}             // A batch is applied after a receive from here
+	onWrite func()                    // Called before every batch, may be nil
+	getting chan struct{}             // Closed when Get starts, if not nil
+	got     chan struct{}             // Get returns after a receive from here, if not nil
+}
+
+func newGated() *gated {
+	return &gated{Memory: NewMemory[string, int]()}
+}
+
+// WriteBatch - reports the batch, waits for the test, then writes to the Memory store
+func (g *gated) WriteBatch(ctx context.Context, writes []Write[string, int]) error {
+	if g.onWrite != nil {
+		g.onWrite()
+	}
+	if g.entered != nil {
+		g.entered <- writes
+		<-g.release
+	}
+	return g.Memory.WriteBatch(ctx, writes)
+}
+
+// Get - reads the Memory store, held by the test if the channels are set
+func (g *gated) Get(ctx context.Context, key string) (int, error) {
+	v, err := g.Memory.Get(ctx, key)
+	if g.getting != nil {
+		close(g.getting)
+		<-g.got
+	}
+	return v, err
+}
+
+// stored - returns the value of a key in the store or -1
+func stored(s Store[string, int], key string) int {
+	v, err := s.Get(context.Background(), key)
+	if err != nil {
+		return -1
+	}
+	return v
+}
+
+func TestWriteThrough(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemory[string, int]()
+	c := NewWriteThrough[string, int](lru.New[string, int](10), db)
+
+	if err := c.Set(ctx, "a", 1); err != nil || stored(db, "a") != 1 {
+		t.Fatalf("Set: %v, stored %d", err, stored(db, "a"))
+	}
+
+	// A failed store write changes neither the store nor the cache
+	db.FailNext(1, nil)
+	if err := c.Set(ctx, "a", 2); !errors.Is(err, ErrInjected) {
+		t.Errorf("Set with a failing store = %v, want ErrInjected", err)
+	}
+	if v, _ := c.Get(ctx, "a"); v != 1 || stored(db, "a") != 1 {
+		t.Errorf("after a failed Set: cache %d, store %d, want 1 and 1", v, stored(db, "a"))
+	}
+
+	// A miss is read through and cached
+	db.Put(ctx, "b", 2)
+	before, _ := db.Calls()
+	for range 2 {
+		if v, err := c.Get(ctx, "b"); v != 2 || err != nil {
+			t.Errorf("Get(b) = %d, %v", v, err)
+		}
+	}
+	if gets, _ := db.Calls(); gets-before != 1 {
+		t.Errorf("%d store reads, want 1", gets-before)
+	}
+}
+
+func TestFailNextWithoutError(t *testing.T) {
+	db := NewMemory[string, int]()
+	db.FailNext(1, nil)
+
+	if err := db.Put(context.Background(), "a", 1); !errors.Is(err, ErrInjected) {
+		t.Errorf("Put = %v, want ErrInjected", err)
+	}
+	if db.Len() != 0 {
+		t.Error("a failed write changed the store")
+	}
+}
+
+func TestWriteBehindRetry(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemory[string, int]()
+	c := NewWriteBehind[string, int](lru.New[string, int](10), db, WithFlushInterval(time.Hour), WithRetry(3, time.Millisecond))
+	defer c.Close(ctx)
+
+	c.Set(ctx, "a", 1)
+	c.Set(ctx, "a", 2) // Replaces the pending write
+
+	db.FailNext(2, errors.New("timeout"))
+	if err := c.Flush(ctx); err != nil {
+		t.Fatalf("Flush with two failures and three attempts: %v", err)
+	}
+	if _, writes := db.Calls(); writes != 1 || stored(db, "a") != 2 || c.Pending() != 0 {
+		t.Errorf("%d writes, stored %d, %d pending, want 1, 2, 0", writes, stored(db, "a"), c.Pending())
+	}
+}
+
+func TestWriteBehindRequeuesFailedBatch(t *testing.T) {
+	ctx := context.Background()
+	db := newGated()
+
+	var failed []Write[string, int]
+	c := NewWriteBehind[string, int](lru.New[string, int](10), db, WithFlushInterval(time.Hour), WithRetry(1, 0),
+		WithOnFlushError(func(writes []Write[string, int], err error) { failed = writes }))
+	defer c.Close(ctx)
+
+	c.Set(ctx, "a", 1)
+	c.Set(ctx, "b", 1)
+
+	// While the failing batch is written, "a" is written again: the newer write must win over the re-queued one
+	db.FailNext(1, nil)
+	db.onWrite = func() {
+		db.onWrite = nil
+		c.Set(ctx, "a", 2)
+	}
+	if err := c.Flush(ctx); !errors.Is(err, ErrInjected) {
+		t.Fatalf("Flush = %v, want ErrInjected", err)
+	}
+	if len(failed) != 2 || c.Pending() != 2 {
+		t.Errorf("OnFlushError got %d writes, %d pending, want 2 and 2", len(failed), c.Pending())
+	}
+
+	if err := c.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if stored(db, "a") != 2 || stored(db, "b") != 1 {
+		t.Errorf("stored a=%d b=%d, want 2 and 1", stored(db, "a"), stored(db, "b"))
+	}
+}
+
+func TestWriteBehindBackPressure(t *testing.T) {
+	ctx := context.Background()
+	db := newGated()
+	db.entered = make(chan []Write[string, int])
+	db.release = make(chan struct{})
+
+	c := NewWriteBehind[string, int](lru.New[string, int](10), db,
+		WithFlushInterval(time.Hour), WithBatchSize(1), WithMaxPending(1))
+
+	// "a" fills the batch and the flusher takes it; "b" fills pending while "a" is being written
+	c.Set(ctx, "a", 1)
+	<-db.entered
+	if err := c.Set(ctx, "b", 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Set(ctx, "b", 3); err != nil {
+		t.Fatalf("rewriting a dirty key must not block: %v", err)
+	}
+
+	// "c" blocks until its context expires
+	short, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer cancel()
+	if err := c.Set(short, "c", 4); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Set at the limit = %v, want DeadlineExceeded", err)
+	}
+
+	// ... or until a flush frees space
+	done := make(chan error, 1)
+	go func() { done <- c.Set(ctx, "c", 4) }()
+
+	db.release <- struct{}{} // "a" is written, the flusher takes "b" and frees space
+	<-db.entered
+	if err := <-done; err != nil {
+		t.Fatalf("Set after a flush freed space: %v", err)
+	}
+
+	// ... or until Close
+	go func() { done <- c.Set(ctx, "d", 5) }()
+	time.Sleep(10 * time.Millisecond) // Lets "d" block
+
+	closed := make(chan error, 1)
+	go func() { closed <- c.Close(ctx) }()
+	if err := <-done; !errors.Is(err, ErrClosed) {
+		t.Errorf("Set blocked during Close = %v, want ErrClosed", err)
+	}
+
+	// Close flushes what is left: "b" is being written, "c" goes in the last flush
+	db.release <- struct{}{}
+	<-db.entered
+	db.release <- struct{}{}
+	if err := <-closed; err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	for key, want := range map[string]int{"a": 1, "b": 3, "c": 4, "d": -1} {
+		if got := stored(db, key); got != want {
+			t.Errorf("stored %s=%d, want %d", key, got, want)
+		}
+	}
+}
+
+func TestWriteBehindClose(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemory[string, int]()
+	c := NewWriteBehind[string, int](lru.New[string, int](10), db, WithFlushInterval(time.Hour))
+
+	c.Set(ctx, "a", 1)
+	c.Set(ctx, "b", 2)
+	c.Delete(ctx, "b")
+	if db.Len() != 0 {
+		t.Fatal("the store was written before a flush")
+	}
+
+	if err := c.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if stored(db, "a") != 1 || stored(db, "b") != -1 {
+		t.Errorf("after Close: a=%d b=%d, want 1 and none", stored(db, "a"), stored(db, "b"))
+	}
+
+	if err := c.Set(ctx, "c", 3); !errors.Is(err, ErrClosed) {
+		t.Errorf("Set after Close = %v, want ErrClosed", err)
+	}
+	if err := c.Close(ctx); !errors.Is(err, ErrClosed) {
+		t.Errorf("second Close = %v, want ErrClosed", err)
+	}
+	if err := c.Flush(ctx); !errors.Is(err, ErrClosed) {
+		t.Errorf("Flush after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestWriteBehindGetEvictedDirtyEntry(t *testing.T) {
+	ctx := context.Background()
+	db := NewMemory[string, int]()
+	c := NewWriteBehind[string, int](lru.New[string, int](1), db, WithFlushInterval(time.Hour))
+	defer c.Close(ctx)
+
+	c.Set(ctx, "a", 1)
+	c.Set(ctx, "b", 2) // Evicts "a" from the cache before it reaches the store
+	c.Delete(ctx, "b")
+
+	if v, err := c.Get(ctx, "a"); v != 1 || err != nil {
+		t.Errorf("Get(a) = %d, %v, want the pending write", v, err)
+	}
+	if _, err := c.Get(ctx, "b"); !errors.Is(err, cache.ErrNotFound) {
+		t.Errorf("Get(b) = %v, want ErrNotFound for a pending delete", err)
+	}
+	if gets, _ := db.Calls(); gets != 0 {
+		t.Errorf("%d store reads, want 0", gets)
+	}
+}
+
+func TestWriteBehindGetDoesNotCacheStaleValue(t *testing.T) {
+	ctx := context.Background()
+	db := newGated()
+	db.Put(ctx, "a", 1)
+
+	c := NewWriteBehind[string, int](lru.New[string, int](10), db, WithFlushInterval(time.Hour))
+	defer c.Close(ctx)
+
+	// Get misses and reads the old value; meanwhile the key is written and the write is flushed
+	db.getting = make(chan struct{})
+	db.got = make(chan struct{})
+	read := make(chan int, 1)
+	go func() {
+		v, _ := c.Get(ctx, "a")
+		read <- v
+	}()
+
+	<-db.getting
+	c.Set(ctx, "a", 2)
+	if err := c.Flush(ctx); err != nil {
+		t.Fatal(err)
+	}
+	db.getting = nil
+	close(db.got)
+
+	if v := <-read; v != 1 {
+		t.Errorf("the racing Get returned %d, want the old value 1", v)
+	}
+	if v, err := c.Get(ctx, "a"); v != 2 || err != nil {
+		t.Errorf("Get after the race = %d, %v, want 2: the old value was cached", v, err)
+	}
+}
@@ -0,0 +1,415 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+)
+
+const (
+	defaultFlushInterval = time.Second
+	defaultBatchSize     = 100
+	defaultMaxPending    = 10_000
+	defaultAttempts      = 3
+	defaultBackoff       = 100 * time.Millisecond
+)
+
+// WriteBehind - a cache that writes to the store asynchronously, in batches
+type WriteBehind[K comparable, V any] struct {
+	cache cache.Cache[K, V]
+	store Store[K, V]
+
+	mu       sync.Mutex
+	pending  map[K]Write[K, V] // Dirty keys waiting for a flush; a later write of a key replaces the earlier one
+	inflight map[K]Write[K, V] // The batch being written now (still visible to Get)
+	loads    map[K]*load       // Keys that Get is reading from the store now
+	space    chan struct{}     // Closed (and replaced) when a flush frees space in pending
+	closed   bool
+	closeErr error // The result of the last flush, set by the flusher before it stops
+
+	interval   time.Duration
+	batchSize  int
+	maxPending int
+	attempts   int
+	backoff    time.Duration
+	onError    func(writes []Write[K, V], err error)
+
+	kick     chan struct{}   // Asks the flusher to flush now (the batch size was reached)
+	requests chan chan error // Synchronous flushes (Flush)
+	stop     chan struct{}
+	stopped  chan struct{}
+}
+
+var _ cache.Cache[string, int] = (*WriteBehind[string, int])(nil)
+
+// load - the reads of one key from the store in progress and the generation of the key's writes
+type load struct {
+	readers int
+	gen     uint64 // Incremented by every write of the key while it is read
+}
+
+// options - settings of a write-behind cache
+type options struct {
+	interval   time.Duration
+	batchSize  int
+	maxPending int
+	attempts   int
+	backoff    time.Duration
+	onError    any // func([]Write[K, V], error), checked in NewWriteBehind
+}
+
+// Option - configures a write-behind cache
+type Option func(*options)
+
+// WithFlushInterval - sets how often the dirty entries are flushed (1 second by default, non-positive values are ignored)
+func WithFlushInterval(d time.Duration) Option {
+	return func(o *options) {
+		if d > 0 {
+			o.interval = d
+		}
+	}
+}
+
+// WithBatchSize - flushes as soon as this many keys are dirty (100 by default)
+func WithBatchSize(n int) Option {
+	return func(o *options) {
+		o.batchSize = max(n, 1)
+	}
+}
+
+// WithMaxPending - the back-pressure limit: Set blocks while this many keys are dirty (10 000 by default)
+func WithMaxPending(n int) Option {
+	return func(o *options) {
+		o.maxPending = max(n, 1)
+	}
+}
+
+// WithRetry - sets the number of attempts to write a batch and the first backoff, doubled after every failure
+// (3 attempts from 100ms by default)
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(o *options) {
+		o.attempts = max(attempts, 1)
+		o.backoff = backoff
+	}
+}
+
+// WithOnFlushError - sets a callback for batches that failed after all attempts (they are kept for the next flush).
+// The type of the callback must match the cache, otherwise NewWriteBehind panics.
+func WithOnFlushError[K comparable, V any](fn func(writes []Write[K, V], err error)) Option {
+	return func(o *options) {
+		o.onError = fn
+	}
+}
+
+// NewWriteBehind - wraps a cache (lru.LRU, cache_ttl.Cache, ...) and a store and starts the flusher.
+// Close must be called to write the last changes and stop the flusher.
+func NewWriteBehind[K comparable, V any](c cache.Cache[K, V], s Store[K, V], opts ...Option) *WriteBehind[K, V] {
+	o := options{
+		interval:   defaultFlushInterval,
+		batchSize:  defaultBatchSize,
+		maxPending: defaultMaxPending,
+		attempts:   defaultAttempts,
+		backoff:    defaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	w := &WriteBehind[K, V]{
+		cache:      c,
+		store:      s,
+		pending:    make(map[K]Write[K, V]),
+		loads:      make(map[K]*load),
+		space:      make(chan struct{}),
+		interval:   o.interval,
+		batchSize:  o.batchSize,
+		maxPending: max(o.maxPending, o.batchSize),
+		attempts:   o.attempts,
+		backoff:    o.backoff,
+		kick:       make(chan struct{}, 1),
+		requests:   make(chan chan error),
+		stop:       make(chan struct{}),
+		stopped:    make(chan struct{}),
+	}
+	if o.onError != nil {
+		fn, ok := o.onError.(func([]Write[K, V], error))
+		if !ok {
+			panic("store: WithOnFlushError: the callback does not match the key and value types")
+		}
+		w.onError = fn
+	}
+
+	go w.run()
+	return w
+}
+
+// Get - returns a value from the cache, from the writes not yet flushed or from the store
+func (w *WriteBehind[K, V]) Get(ctx context.Context, key K) (V, error) {
+	v, err := w.cache.Get(ctx, key)
+	if !errors.Is(err, cache.ErrNotFound) {
+		return v, err // A hit or a canceled ctx
+	}
+
+	// The cache may have evicted a dirty entry: the store does not have it yet
+	w.mu.Lock()
+	if pw, ok := w.dirty(key); ok {
+		w.mu.Unlock()
+		if pw.Deleted {
+			return v, cache.ErrNotFound
+		}
+		return pw.Value, nil
+	}
+	l := w.loads[key]
+	if l == nil {
+		l = &load{}
+		w.loads[key] = l
+	}
+	l.readers++
+	gen := l.gen
+	w.mu.Unlock()
+
+	v, err = w.store.Get(ctx, key)
+
+	// A write that came while the store was read is newer than the loaded value,
+	// even if it has already been flushed: the value is cached only if the key was not written since
+	w.mu.Lock()
+	if err == nil && l.gen == gen {
+		w.cache.Set(ctx, key, v)
+	}
+	if l.readers--; l.readers == 0 {
+		delete(w.loads, key)
+	}
+	w.mu.Unlock()
+
+	return v, err
+}
+
+// Set - writes to the cache at once and schedules the write to the store.
+// Blocks while the pending writes are at the limit (back-pressure).
+func (w *WriteBehind[K, V]) Set(ctx context.Context, key K, value V) error {
+	return w.write(ctx, Write[K, V]{Key: key, Value: value})
+}
+
+// Delete - deletes from the cache at once and schedules the deletion in the store
+func (w *WriteBehind[K, V]) Delete(ctx context.Context, key K) error {
+	return w.write(ctx, Write[K, V]{Key: key, Deleted: true})
+}
+
+// Pending - returns the number of dirty keys not yet written to the store
+func (w *WriteBehind[K, V]) Pending() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return len(w.pending) + len(w.inflight)
+}
+
+// Flush - writes all the dirty entries now and waits for the result
+func (w *WriteBehind[K, V]) Flush(ctx context.Context) error {
+	done := make(chan error, 1)
+
+	select {
+	case w.requests <- done:
+	case <-w.stopped:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close - stops accepting writes, flushes the dirty entries and stops the flusher.
+// Returns the error of the last flush: the entries that could not be written then are lost.
+func (w *WriteBehind[K, V]) Close(ctx context.Context) error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return ErrClosed
+	}
+	w.closed = true
+	close(w.space) // Wakes up the writers blocked by back-pressure, they see closed
+	w.mu.Unlock()
+
+	close(w.stop)
+
+	select {
+	case <-w.stopped:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	return w.closeErr
+}
+
+// write - waits for space in pending, then updates the cache and records the write
+func (w *WriteBehind[K, V]) write(ctx context.Context, pw Write[K, V]) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	for {
+		if w.closed {
+			w.mu.Unlock()
+			return ErrClosed
+		}
+
+		// A key that is already dirty takes no extra space
+		if _, ok := w.pending[pw.Key]; ok || len(w.pending) < w.maxPending {
+			break
+		}
+
+		space := w.space
+		w.mu.Unlock()
+		w.flushSoon()
+
+		select {
+		case <-space:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		w.mu.Lock()
+	}
+
+	// The cache is changed under w.mu, so the cache and pending agree on the order of writes of a key
+	var err error
+	if pw.Deleted {
+		err = w.cache.Delete(ctx, pw.Key)
+	} else {
+		err = w.cache.Set(ctx, pw.Key, pw.Value)
+	}
+	if err != nil && !errors.Is(err, cache.ErrTooLarge) {
+		w.mu.Unlock()
+		return err
+	}
+
+	w.pending[pw.Key] = pw
+	if l, ok := w.loads[pw.Key]; ok {
+		l.gen++
+	}
+	full := len(w.pending) >= w.batchSize
+	w.mu.Unlock()
+
+	if full {
+		w.flushSoon()
+	}
+	return nil
+}
+
+// dirty - returns the latest write of a key that is not in the store yet (w.mu must be held)
+func (w *WriteBehind[K, V]) dirty(key K) (Write[K, V], bool) {
+	if pw, ok := w.pending[key]; ok {
+		return pw, true
+	}
+	pw, ok := w.inflight[key]
+	return pw, ok
+}
+
+// flushSoon - asks the flusher to flush without waiting for the interval
+func (w *WriteBehind[K, V]) flushSoon() {
+	select {
+	case w.kick <- struct{}{}:
+	default: // A flush is already requested
+	}
+}
+
+// run - the flusher: flushes on the interval, on a full batch and on request, and once more on close
+func (w *WriteBehind[K, V]) run() {
+	defer close(w.stopped)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			w.flush()
+		case <-w.kick:
+			w.flush()
+		case done := <-w.requests:
+			done <- w.flush()
+		case <-w.stop:
+			w.closeErr = w.flush()
+			return
+		}
+	}
+}
+
+// flush - writes all pending entries in batches of at most batchSize; failed batches go back to pending
+func (w *WriteBehind[K, V]) flush() error {
+	for {
+		w.mu.Lock()
+		if len(w.pending) == 0 {
+			w.mu.Unlock()
+			return nil
+		}
+
+		// Take up to batchSize entries out of pending and make them visible to Get as inflight
+		size := min(len(w.pending), w.batchSize)
+		w.inflight = make(map[K]Write[K, V], size)
+		batch := make([]Write[K, V], 0, size)
+		for key, pw := range w.pending {
+			if len(batch) == w.batchSize {
+				break
+			}
+			batch = append(batch, pw)
+			w.inflight[key] = pw
+			delete(w.pending, key)
+		}
+		w.signalSpace()
+		w.mu.Unlock()
+
+		err := w.writeWithRetry(batch)
+
+		w.mu.Lock()
+		if err != nil {
+			// Back to pending, unless the key was written again during the flush (the newer write wins)
+			for _, pw := range batch {
+				if _, ok := w.pending[pw.Key]; !ok {
+					w.pending[pw.Key] = pw
+				}
+			}
+		}
+		w.inflight = nil
+		w.mu.Unlock()
+
+		if err != nil {
+			if w.onError != nil {
+				w.onError(batch, err)
+			}
+			return err // The rest waits for the next flush: the store is failing now
+		}
+	}
+}
+
+// writeWithRetry - writes a batch, retrying with exponential backoff
+func (w *WriteBehind[K, V]) writeWithRetry(batch []Write[K, V]) error {
+	backoff := w.backoff
+
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = apply(context.Background(), w.store, batch); err == nil || attempt == w.attempts {
+			return err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// signalSpace - wakes up the writers blocked by back-pressure (w.mu must be held)
+func (w *WriteBehind[K, V]) signalSpace() {
+	if w.closed {
+		return // space is already closed by Close
+	}
+	close(w.space)
+	w.space = make(chan struct{})
+}
@@ -0,0 +1,58 @@
+package store
+
+import (
+	"context"
+	"errors"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+)
+
+// WriteThrough - a cache that writes to the store synchronously and reads through it on a miss
+type WriteThrough[K comparable, V any] struct {
+	cache cache.Cache[K, V]
+	store Store[K, V]
+}
+
+var _ cache.Cache[string, int] = (*WriteThrough[string, int])(nil)
+
+// NewWriteThrough - wraps a cache (lru.LRU, cache_ttl.Cache, ...) and a store
+func NewWriteThrough[K comparable, V any](c cache.Cache[K, V], s Store[K, V]) *WriteThrough[K, V] {
+	return &WriteThrough[K, V]{cache: c, store: s}
+}
+
+// Get - returns a cached value or loads it from the store and caches it
+func (w *WriteThrough[K, V]) Get(ctx context.Context, key K) (V, error) {
+	v, err := w.cache.Get(ctx, key)
+	if !errors.Is(err, cache.ErrNotFound) {
+		return v, err // A hit or a canceled ctx
+	}
+
+	v, err = w.store.Get(ctx, key)
+	if err != nil {
+		return v, err
+	}
+
+	w.cache.Set(ctx, key, v)
+	return v, nil
+}
+
+// Set - writes to the store and, if it succeeded, to the cache
+func (w *WriteThrough[K, V]) Set(ctx context.Context, key K, value V) error {
+	if err := w.store.Put(ctx, key, value); err != nil {
+		return err
+	}
+
+	// A value the cache rejects (cache.ErrTooLarge) is still written, it is just not cached
+	if err := w.cache.Set(ctx, key, value); err != nil && !errors.Is(err, cache.ErrTooLarge) {
+		return err
+	}
+	return nil
+}
+
+// Delete - deletes from the store and then from the cache
+func (w *WriteThrough[K, V]) Delete(ctx context.Context, key K) error {
+	if err := w.store.Delete(ctx, key); err != nil {
+		return err
+	}
+	return w.cache.Delete(ctx, key)
+}
@@ -0,0 +1,55 @@
+package trace
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strings"
+)
+
+// Example demonstrates replaying a trace against LRU, LFU, ARC, 2Q and W-TinyLFU
+func Example() {
+	// A small hand-written trace: "a" and "b" are hot, then a scan of one-off keys passes through
+	log := `# key  timestamp
+a 1
+b 2
+a 3
+b 4
+x1 5
+x2 6
+x3 7
+a 8
+b 9
+`
+	keys, _ := Read(strings.NewReader(log))
+	fmt.Printf("parsed %d accesses: %v\n", len(keys), keys)
+
+	results, _ := Replay(keys, 2)
+	for _, result := range results {
+		fmt.Println(result)
+	}
+
+	// A bigger synthetic trace: Zipf-distributed hot keys interrupted by long scans
+	fmt.Println()
+	Compare(syntheticTrace(200_000), os.Stdout, 100, 1000)
+}
+
+// syntheticTrace - writes n accesses: mostly Zipf-distributed keys, with a scan of unique keys every 10000 accesses
+func syntheticTrace(n int) *bytes.Buffer {
+	r := rand.New(rand.NewPCG(1, 2))
+	zipf := rand.NewZipf(r, 1.1, 1, 10_000)
+
+	var buf bytes.Buffer
+	scan := 0
+	for i := 0; i < n; i++ {
+		if i%10_000 < 2_000 {
+			fmt.Fprintf(&buf, "scan:%d\n", scan)
+			scan++
+			continue
+		}
+		fmt.Fprintf(&buf, "key:%d\n", zipf.Uint64())
+	}
+
+	return &buf
+}
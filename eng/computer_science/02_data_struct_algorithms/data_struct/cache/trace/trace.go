@@ -0,0 +1,158 @@
+/*
+Trace Replay
+
+What is it?
+A harness that replays a key-access log (a trace) against several eviction policies
+and reports the hit ratio of each of them side by side.
+
+Why is it needed?
+- The best eviction policy depends on the workload: LRU wins on recency-heavy traces,
+  LFU on stable popularity, ARC and 2Q on traces with scans.
+- Guessing is unreliable; replaying a real log of the application gives the answer in seconds.
+
+How does it work?
+- A trace is text with one access per line. The first whitespace-separated field is the key,
+  the other fields (timestamps, sizes) are ignored. Empty lines and lines starting with '#' are skipped.
+- Every access is a Get; a miss is followed by a Set, like a read-through cache in front of a database.
+- Every policy sees the same trace with the same capacity, so the hit ratios are comparable.
+
+Policies:
+- LRU (data_struct/LRU), LFU (data_struct/LFU), ARC (data_struct/ARC), 2Q (data_struct/two_queue),
+  W-TinyLFU (data_struct/TinyLFU).
+- Any other cache.Cache can be added with a Policy value.
+
+Examples of using Trace Replay:
+See the example.go file.
+*/
+
+package trace
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/ARC"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/LFU"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/LRU"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/TinyLFU"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/two_queue"
+)
+
+// Policy - a named constructor of a cache with a given capacity
+type Policy struct {
+	Name string
+	New  func(capacity int) cache.Cache[string, struct{}]
+}
+
+// Policies - returns the built-in policies: LRU, LFU, ARC, 2Q and W-TinyLFU
+func Policies() []Policy {
+	return []Policy{
+		{"LRU", func(capacity int) cache.Cache[string, struct{}] { return lru.New[string, struct{}](capacity) }},
+		{"LFU", func(capacity int) cache.Cache[string, struct{}] { return lfu.New[string, struct{}](capacity) }},
+		{"ARC", func(capacity int) cache.Cache[string, struct{}] { return arc.New[string, struct{}](capacity) }},
+		{"2Q", func(capacity int) cache.Cache[string, struct{}] { return two_queue.New[string, struct{}](capacity) }},
+		{"W-TinyLFU", func(capacity int) cache.Cache[string, struct{}] { return tinylfu.New[string, struct{}](capacity) }},
+	}
+}
+
+// Result - the outcome of one policy on one trace
+type Result struct {
+	Policy   string
+	Capacity int
+	Hits     int
+	Misses   int
+}
+
+// HitRatio - returns hits / (hits + misses)
+func (r Result) HitRatio() float64 {
+	total := r.Hits + r.Misses
+	if total == 0 {
+		return 0
+	}
+	return float64(r.Hits) / float64(total)
+}
+
+// String - formats the result as one line
+func (r Result) String() string {
+	return fmt.Sprintf("%-9s capacity %-6d hits %-8d misses %-8d hit ratio %6.2f%%",
+		r.Policy, r.Capacity, r.Hits, r.Misses, r.HitRatio()*100)
+}
+
+// Read - parses a trace: one access per line, the key is the first field
+func Read(r io.Reader) ([]string, error) {
+	var keys []string
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys = append(keys, strings.Fields(line)[0])
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("trace: read: %w", err)
+	}
+	return keys, nil
+}
+
+// Replay - runs the trace against every policy (the built-in ones if none are given)
+func Replay(keys []string, capacity int, policies ...Policy) ([]Result, error) {
+	if len(policies) == 0 {
+		policies = Policies()
+	}
+
+	ctx := context.Background()
+	results := make([]Result, 0, len(policies))
+
+	for _, policy := range policies {
+		c := policy.New(capacity)
+		result := Result{Policy: policy.Name, Capacity: capacity}
+
+		for _, key := range keys {
+			_, err := c.Get(ctx, key)
+			switch {
+			case err == nil:
+				result.Hits++
+			case errors.Is(err, cache.ErrNotFound):
+				result.Misses++
+				if err := c.Set(ctx, key, struct{}{}); err != nil {
+					return nil, fmt.Errorf("trace: %s: set %q: %w", policy.Name, key, err)
+				}
+			default:
+				return nil, fmt.Errorf("trace: %s: get %q: %w", policy.Name, key, err)
+			}
+		}
+
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+// Compare - reads a trace and replays it for every capacity, writing a table to w
+func Compare(r io.Reader, w io.Writer, capacities ...int) error {
+	keys, err := Read(r)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "trace: %d accesses\n", len(keys))
+	for _, capacity := range capacities {
+		results, err := Replay(keys, capacity)
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			fmt.Fprintln(w, result)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,248 @@
+/*
+Count-Min Sketch (frequencies of a stream)
+
+What is it?
+A Count-Min Sketch estimates how many times every key occurred in a stream, using a fixed table
+of depth rows and width counters, no matter how many different keys there are. It never underestimates:
+the estimate is the true count plus, with a high probability, at most epsilon * N (N — the total of all counts).
+
+Why is it needed?
+- An exact frequency map (hash_table.CountFrequency) grows with the number of distinct keys.
+  For an unbounded stream (cache requests, clicks, network flows) it grows without a limit.
+- The sketch takes width * depth counters: with epsilon = 0.001 and delta = 0.01 that is 2719 * 5 counters,
+  about 106 KB, for any number of keys.
+- Combined with a small heap it finds the most frequent keys (HeavyHitters): the hottest keys of a cache,
+  the heaviest users of an API.
+
+What's the point?
+- Every row has its own hash function that maps a key to one of width counters.
+- Add increments the counter of the key in every row, Estimate returns the minimum of those counters.
+- Other keys that fall into the same counter only increase it, so every counter is >= the true count,
+  and the minimum over the rows is the least polluted one.
+- Sizing: width = ceil(e / epsilon), depth = ceil(ln(1 / delta)) gives
+  estimate <= count + epsilon * N with probability 1 - delta.
+
+When to use?
+- Frequencies in a stream when an overestimate of epsilon * N is acceptable.
+- Finding heavy hitters (top-K) together with a heap (HeavyHitters) — see also SpaceSaving.
+- Admission policies of caches (TinyLFU keeps a similar sketch of 4-bit counters).
+
+How does it work?
+- Double hashing, as in data_struct/bloom: one 64-bit hash h gives h1 = h and h2 = hasher.Mix64(h) | 1,
+  and the column of row i is (h1 + i*h2) mod width.
+- Conservative update (on by default, WithConservativeUpdate): Add raises every counter of the key
+  only up to min + n instead of adding n to all of them. Counters that are already higher because of other keys
+  are left alone, so the overestimation shrinks a lot on skewed streams. The estimate still never undercounts.
+- Merge adds the counters of two sketches with the same size and hasher (e.g. the sketches of several nodes).
+  With conservative update the merged sketch stays an upper bound, but is less tight than one built from the whole stream.
+
+### Complexity
+
+| Operation | Time (O) | Space (O) |
+|:---|:---:|:---:|
+| Add | O(depth) | O(1) |
+| Estimate | O(depth) | O(1) |
+| Merge | O(width * depth) | O(width * depth) |
+| Memory | — | O(width * depth) |
+
+Heavy hitters (heavy_hitters.go) and Space-Saving (space_saving.go):
+- HeavyHitters feeds every key to a sketch and keeps the k keys with the largest estimates in a min-heap
+  (heap.Heap from data_struct/heap): a new key enters the top if its estimate beats the root, which is evicted.
+- SpaceSaving keeps exactly k counters without a sketch: an unknown key replaces the smallest counter
+  and inherits its count, which becomes the error bound of the new key. Every key with a frequency above N/k
+  is guaranteed to be in the top.
+- Tracked (tracked.go) wraps a cache and reports every requested key to a tracker, to find the hottest keys of the cache.
+
+Examples of using Count-Min Sketch:
+See the example.go file.
+*/
+
+package count_min
+
+import (
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
+)
+
+// ErrIncompatible - the sketches have different sizes
+var ErrIncompatible = errors.New("count_min: sketches have different sizes")
+
+// Sketch - a Count-Min Sketch, safe for concurrent use
+type Sketch[K any] struct {
+	width        uint64
+	depth        uint64
+	counters     []uint64 // depth rows of width counters
+	total        uint64   // Sum of all added counts (N)
+	conservative bool
+	hasher       hasher.Hasher[K]
+	mu           *sync.RWMutex
+}
+
+// Option - configures a sketch
+type Option[K any] func(*Sketch[K])
+
+// WithConservativeUpdate - turns the conservative update on or off (on by default)
+func WithConservativeUpdate[K any](enabled bool) Option[K] {
+	return func(s *Sketch[K]) {
+		s.conservative = enabled
+	}
+}
+
+// WithHasher - sets the hash function of the keys (xxHash64 with seed 0 by default).
+// Only sketches with the same hasher can be merged.
+func WithHasher[K any](h hasher.Hasher[K]) Option[K] {
+	return func(s *Sketch[K]) {
+		if h != nil {
+			s.hasher = h
+		}
+	}
+}
+
+// New - creates a sketch whose estimates exceed the true counts by at most epsilon * N
+// with probability 1 - delta (e.g. New[string](0.001, 0.01)).
+// epsilon and delta outside (0, 1) are treated as 0.001 and 0.01.
+func New[K any](epsilon, delta float64, opts ...Option[K]) *Sketch[K] {
+	if epsilon <= 0 || epsilon >= 1 {
+		epsilon = 0.001
+	}
+	if delta <= 0 || delta >= 1 {
+		delta = 0.01
+	}
+
+	width := int(math.Ceil(math.E / epsilon))
+	depth := int(math.Ceil(math.Log(1 / delta)))
+	return NewWithSize[K](width, depth, opts...)
+}
+
+// NewWithSize - creates a sketch with depth rows of width counters
+func NewWithSize[K any](width, depth int, opts ...Option[K]) *Sketch[K] {
+	s := &Sketch[K]{
+		width:        uint64(max(width, 1)),
+		depth:        uint64(max(depth, 1)),
+		conservative: true,
+		hasher:       hasher.NewXXHash64WithSeed[K](0),
+		mu:           &sync.RWMutex{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.counters = make([]uint64, s.width*s.depth)
+	return s
+}
+
+// Add - counts n more occurrences of a key and returns its new estimate
+func (s *Sketch[K]) Add(key K, n uint64) uint64 {
+	h1, h2 := s.hashes(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.total += n
+
+	if !s.conservative {
+		estimate := uint64(math.MaxUint64)
+		for row := range s.depth {
+			c := &s.counters[s.cell(row, h1, h2)]
+			*c += n
+			estimate = min(estimate, *c)
+		}
+		return estimate
+	}
+
+	// Conservative update: no counter needs to be higher than the new estimate
+	estimate := s.estimate(h1, h2) + n
+	for row := range s.depth {
+		c := &s.counters[s.cell(row, h1, h2)]
+		*c = max(*c, estimate)
+	}
+	return estimate
+}
+
+// Estimate - returns the estimated number of occurrences of a key (never less than the true one)
+func (s *Sketch[K]) Estimate(key K) uint64 {
+	h1, h2 := s.hashes(key)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.estimate(h1, h2)
+}
+
+// Total - returns the sum of all added counts (N)
+func (s *Sketch[K]) Total() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.total
+}
+
+// ErrorBound - returns the additive error epsilon * N of the estimates for the current total
+func (s *Sketch[K]) ErrorBound() uint64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return uint64(math.Ceil(math.E / float64(s.width) * float64(s.total)))
+}
+
+// Width - returns the number of counters in a row
+func (s *Sketch[K]) Width() int {
+	return int(s.width)
+}
+
+// Depth - returns the number of rows
+func (s *Sketch[K]) Depth() int {
+	return int(s.depth)
+}
+
+// Merge - adds the counters of another sketch with the same size and hasher
+func (s *Sketch[K]) Merge(other *Sketch[K]) error {
+	if s.width != other.width || s.depth != other.depth {
+		return ErrIncompatible
+	}
+
+	other.mu.RLock()
+	counters := append([]uint64(nil), other.counters...)
+	total := other.total
+	other.mu.RUnlock()
+
+	s.mu.Lock()
+	for i, c := range counters {
+		s.counters[i] += c
+	}
+	s.total += total
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Reset - sets all counters to zero
+func (s *Sketch[K]) Reset() {
+	s.mu.Lock()
+	clear(s.counters)
+	s.total = 0
+	s.mu.Unlock()
+}
+
+// hashes - returns the two base hashes of a key for double hashing
+func (s *Sketch[K]) hashes(key K) (h1, h2 uint64) {
+	h := s.hasher.Hash(key)
+	return h, hasher.Mix64(h) | 1
+}
+
+// cell - returns the index of the counter of a row
+func (s *Sketch[K]) cell(row, h1, h2 uint64) uint64 {
+	return row*s.width + (h1+row*h2)%s.width
+}
+
+// estimate - returns the minimum of the counters of a key (s.mu must be held)
+func (s *Sketch[K]) estimate(h1, h2 uint64) uint64 {
+	estimate := uint64(math.MaxUint64)
+	for row := range s.depth {
+		estimate = min(estimate, s.counters[s.cell(row, h1, h2)])
+	}
+	return estimate
+}
@@ -0,0 +1,115 @@
+package count_min
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/LRU"
+)
+
+// Example demonstrates the use of a Count-Min Sketch and of the top-k trackers
+func Example() {
+	// A skewed stream, like the requests to a cache: a few keys are very hot, most keys are rare
+	stream := zipfStream(1000000, 100000)
+
+	// Exact frequencies for reference (what hash_table.CountFrequency computes, O(distinct) memory)
+	exact := make(map[string]uint64)
+	for _, key := range stream {
+		exact[key]++
+	}
+
+	// 1. Count-Min Sketch with and without conservative update
+	plain := New[string](0.001, 0.01, WithConservativeUpdate[string](false))
+	conservative := New[string](0.001, 0.01)
+	for _, key := range stream {
+		plain.Add(key, 1)
+		conservative.Add(key, 1)
+	}
+	fmt.Printf("Sketch %d x %d counters (%d KB) for %d distinct keys, bound epsilon*N = %d\n",
+		conservative.Width(), conservative.Depth(), conservative.Width()*conservative.Depth()*8/1024,
+		len(exact), conservative.ErrorBound())
+
+	for _, s := range []struct {
+		name   string
+		sketch *Sketch[string]
+	}{{"plain", plain}, {"conservative", conservative}} {
+		var sum, worst uint64
+		for key, count := range exact {
+			over := s.sketch.Estimate(key) - count // Never negative: the sketch does not undercount
+			sum += over
+			worst = max(worst, over)
+		}
+		fmt.Printf("%-12s mean overestimation %.2f, max %d\n", s.name, float64(sum)/float64(len(exact)), worst)
+	}
+
+	// 2. Top-10: HeavyHitters (sketch + min-heap) and Space-Saving against the exact answer
+	CompareTopK(stream, exact, 10)
+
+	// 3. The hottest keys of a cache
+	ctx := context.Background()
+	tracked := NewTracked[string, int](lru.New[string, int](1000), NewSpaceSaving[string](50))
+	for i, key := range stream[:100000] {
+		if _, err := tracked.Get(ctx, key); err != nil {
+			tracked.Set(ctx, key, i) // A miss: load the value into the cache
+		}
+	}
+	fmt.Print("Hottest cache keys:")
+	for _, item := range tracked.Top()[:3] {
+		fmt.Printf(" %s (%d requests)", item.Key, item.Count)
+	}
+	fmt.Println()
+}
+
+// CompareTopK - finds the k most frequent keys of a stream with HeavyHitters and with SpaceSaving
+// (with k and with 10*k counters) and prints how many of the true top-k keys each of them found
+// and the largest error of their counts
+func CompareTopK(stream []string, exact map[string]uint64, k int) {
+	keys := make([]string, 0, len(exact))
+	for key := range exact {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b string) int { return cmp.Compare(exact[b], exact[a]) })
+	trueTop := keys[:k]
+
+	trackers := []struct {
+		name    string
+		tracker Tracker[string]
+	}{
+		{fmt.Sprintf("heavy hitters k=%d", k), NewHeavyHitters(k, New[string](0.001, 0.01))},
+		{fmt.Sprintf("space-saving k=%d", k), NewSpaceSaving[string](k)},
+		{fmt.Sprintf("space-saving k=%d", 10*k), NewSpaceSaving[string](10 * k)},
+	}
+
+	for _, t := range trackers {
+		for _, key := range stream {
+			t.tracker.Add(key, 1)
+		}
+
+		top := t.tracker.Top()[:k]
+		found := 0
+		var worst uint64
+		for _, item := range top {
+			if slices.Contains(trueTop, item.Key) {
+				found++
+			}
+			worst = max(worst, item.Count-exact[item.Key])
+		}
+		fmt.Printf("%-22s found %d of the true top-%d, max count error %d, #1 %s: %d (exact %d)\n",
+			t.name, found, k, worst, top[0].Key, top[0].Count, exact[top[0].Key])
+	}
+}
+
+// zipfStream - returns n keys drawn from a Zipf distribution over distinct keys
+func zipfStream(n, distinct int) []string {
+	zipf := rand.NewZipf(rand.New(rand.NewPCG(1, 2)), 1.1, 1, uint64(distinct-1))
+
+	stream := make([]string, n)
+	for i := range stream {
+		stream[i] = "key:" + strconv.FormatUint(zipf.Uint64(), 10)
+	}
+	return stream
+}
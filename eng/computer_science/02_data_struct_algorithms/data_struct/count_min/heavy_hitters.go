@@ -0,0 +1,122 @@
+package count_min
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/heap"
+)
+
+// Item - a key of the top with its estimated count
+type Item[K any] struct {
+	Key   K
+	Count uint64
+	Error uint64 // Max overestimation of Count (SpaceSaving only; for HeavyHitters see Sketch.ErrorBound)
+}
+
+// Tracker - finds the most frequent keys of a stream; HeavyHitters and SpaceSaving implement it
+type Tracker[K comparable] interface {
+	// Add - counts n more occurrences of a key
+	Add(key K, n uint64)
+	// Top - returns the tracked keys, the most frequent first
+	Top() []Item[K]
+}
+
+var (
+	_ Tracker[string] = (*HeavyHitters[string])(nil)
+	_ Tracker[string] = (*SpaceSaving[string])(nil)
+)
+
+// hitter - a key of the top with its position in the heap
+type hitter[K any] struct {
+	key   K
+	count uint64
+	error uint64
+	index int
+}
+
+// newTopHeap - creates a min-heap of hitters by count that keeps their positions up to date
+func newTopHeap[K any]() *heap.Heap[*hitter[K]] {
+	return heap.NewIndexed(
+		func(a, b *hitter[K]) bool { return a.count < b.count },
+		func(h *hitter[K], i int) { h.index = i },
+	)
+}
+
+// sortedTop - returns the hitters as items, the most frequent first
+func sortedTop[K comparable](top map[K]*hitter[K]) []Item[K] {
+	items := make([]Item[K], 0, len(top))
+	for _, h := range top {
+		items = append(items, Item[K]{Key: h.key, Count: h.count, Error: h.error})
+	}
+	slices.SortFunc(items, func(a, b Item[K]) int { return cmp.Compare(b.Count, a.Count) })
+	return items
+}
+
+// HeavyHitters - an approximate top-k over a Count-Min Sketch, safe for concurrent use.
+// Every key is counted by the sketch; the k keys with the largest estimates are kept in a min-heap,
+// so the root is the weakest member of the top and the only candidate for eviction.
+type HeavyHitters[K comparable] struct {
+	k      int
+	sketch *Sketch[K]
+	heap   *heap.Heap[*hitter[K]] // Min-heap by the estimated count
+	top    map[K]*hitter[K]       // Key -> its element in the heap
+	mu     *sync.Mutex
+}
+
+// NewHeavyHitters - creates a tracker of the k most frequent keys counted by the sketch
+func NewHeavyHitters[K comparable](k int, sketch *Sketch[K]) *HeavyHitters[K] {
+	k = max(k, 1)
+	return &HeavyHitters[K]{
+		k:      k,
+		sketch: sketch,
+		heap:   newTopHeap[K](),
+		top:    make(map[K]*hitter[K], k),
+		mu:     &sync.Mutex{},
+	}
+}
+
+// Add - counts n more occurrences of a key and updates the top (O(depth + log k))
+func (hh *HeavyHitters[K]) Add(key K, n uint64) {
+	hh.mu.Lock()
+	defer hh.mu.Unlock()
+
+	estimate := hh.sketch.Add(key, n)
+
+	if h, ok := hh.top[key]; ok {
+		h.count = estimate
+		hh.heap.Fix(h.index)
+		return
+	}
+
+	if hh.heap.Len() < hh.k {
+		h := &hitter[K]{key: key, count: estimate}
+		hh.top[key] = h
+		hh.heap.Push(h)
+		return
+	}
+
+	// The key enters the top only if it beats the weakest member
+	if root, _ := hh.heap.Peek(); estimate > root.count {
+		hh.heap.Pop()
+		delete(hh.top, root.key)
+
+		h := &hitter[K]{key: key, count: estimate}
+		hh.top[key] = h
+		hh.heap.Push(h)
+	}
+}
+
+// Top - returns the top keys with their estimates, the most frequent first
+func (hh *HeavyHitters[K]) Top() []Item[K] {
+	hh.mu.Lock()
+	defer hh.mu.Unlock()
+
+	return sortedTop(hh.top)
+}
+
+// Sketch - returns the underlying sketch (e.g. to estimate keys outside the top)
+func (hh *HeavyHitters[K]) Sketch() *Sketch[K] {
+	return hh.sketch
+}
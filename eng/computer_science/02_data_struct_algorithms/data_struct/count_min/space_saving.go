@@ -0,0 +1,77 @@
+package count_min
+
+import (
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/heap"
+)
+
+// SpaceSaving - the Space-Saving algorithm (Metwally et al.), safe for concurrent use.
+// It monitors exactly k keys. A monitored key just increments its counter. An unknown key replaces
+// the key with the smallest counter m and starts from m + n: it may have occurred up to m times before,
+// so m is remembered as its error. Every key that occurs more than N/k times is always monitored,
+// and Count - Error <= true count <= Count.
+type SpaceSaving[K comparable] struct {
+	k     int
+	total uint64
+	heap  *heap.Heap[*hitter[K]] // Min-heap by count
+	top   map[K]*hitter[K]
+	mu    *sync.Mutex
+}
+
+// NewSpaceSaving - creates a tracker with k counters
+func NewSpaceSaving[K comparable](k int) *SpaceSaving[K] {
+	k = max(k, 1)
+	return &SpaceSaving[K]{
+		k:    k,
+		heap: newTopHeap[K](),
+		top:  make(map[K]*hitter[K], k),
+		mu:   &sync.Mutex{},
+	}
+}
+
+// Add - counts n more occurrences of a key (O(log k))
+func (ss *SpaceSaving[K]) Add(key K, n uint64) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	ss.total += n
+
+	if h, ok := ss.top[key]; ok {
+		h.count += n
+		ss.heap.Fix(h.index)
+		return
+	}
+
+	if ss.heap.Len() < ss.k {
+		h := &hitter[K]{key: key, count: n}
+		ss.top[key] = h
+		ss.heap.Push(h)
+		return
+	}
+
+	// Replace the smallest counter: the new key inherits its count as the error
+	root, _ := ss.heap.Peek()
+	delete(ss.top, root.key)
+	root.key = key
+	root.error = root.count
+	root.count += n
+	ss.top[key] = root
+	ss.heap.Fix(root.index)
+}
+
+// Top - returns the monitored keys with their counts and errors, the most frequent first
+func (ss *SpaceSaving[K]) Top() []Item[K] {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	return sortedTop(ss.top)
+}
+
+// Total - returns the sum of all added counts (N)
+func (ss *SpaceSaving[K]) Total() uint64 {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+
+	return ss.total
+}
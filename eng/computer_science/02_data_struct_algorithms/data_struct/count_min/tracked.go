@@ -0,0 +1,47 @@
+package count_min
+
+import (
+	"context"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+)
+
+// Tracked - a cache decorator that reports every requested key to a tracker,
+// so Top shows the hottest keys of the cache (hits and misses alike) in a fixed amount of memory
+type Tracked[K comparable, V any] struct {
+	cache   cache.Cache[K, V]
+	tracker Tracker[K]
+}
+
+var _ cache.Cache[string, int] = (*Tracked[string, int])(nil)
+
+// NewTracked - wraps a cache (lru.LRU, cache_ttl.Cache, ...) with a tracker (HeavyHitters or SpaceSaving)
+func NewTracked[K comparable, V any](c cache.Cache[K, V], t Tracker[K]) *Tracked[K, V] {
+	return &Tracked[K, V]{cache: c, tracker: t}
+}
+
+// Get - counts the key and returns its value from the cache
+func (t *Tracked[K, V]) Get(ctx context.Context, key K) (V, error) {
+	var zero V
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	t.tracker.Add(key, 1)
+	return t.cache.Get(ctx, key)
+}
+
+// Set - stores the value (writes are not counted)
+func (t *Tracked[K, V]) Set(ctx context.Context, key K, value V) error {
+	return t.cache.Set(ctx, key, value)
+}
+
+// Delete - removes the key from the cache
+func (t *Tracked[K, V]) Delete(ctx context.Context, key K) error {
+	return t.cache.Delete(ctx, key)
+}
+
+// Top - returns the most requested keys, the hottest first
+func (t *Tracked[K, V]) Top() []Item[K] {
+	return t.tracker.Top()
+}
@@ -0,0 +1,389 @@
+/*
+Cuckoo Filter
+
+What is it?
+A cuckoo filter is a probabilistic set, like a Bloom filter (data_struct/bloom), that also supports deletion.
+Instead of bits it stores a short fingerprint of every key in a cuckoo hash table: an array of buckets
+with 4 slots each. A key may live in one of two buckets, and a lookup reads only those two buckets.
+
+Why is it needed?
+- A standard Bloom filter cannot delete keys; a counting Bloom filter can, but takes 4 times more memory.
+- A cuckoo filter deletes keys and, when its table is nearly full and the false-positive rate is below about 0.4%,
+  uses less space per key than a Bloom filter (f/load bits against 1.44 * log2(1/p)).
+- A lookup touches 2 buckets (2 cache lines at most) instead of k random bits.
+
+What's the point?
+- Every key gives a fingerprint fp (f bits of its hash, never 0) and a first bucket i1.
+- The second bucket is i2 = i1 XOR hash(fp) (partial-key cuckoo hashing). It is computed from the fingerprint alone,
+  so a fingerprint can be moved to its other bucket without knowing the key, and i1 = i2 XOR hash(fp) as well.
+- Insert puts fp into a free slot of i1 or i2. If both buckets are full, it kicks a random fingerprint out
+  of one of them and moves it to its alternate bucket, which may kick out another one, and so on.
+- The number of kick-outs is bounded (WithMaxKicks). If the chain does not end, the last homeless fingerprint
+  is kept in a small stash (WithStashSize) instead of being lost. Insert reports a full filter only when
+  the stash is full too; the kick-outs are undone then, so the keys already in the filter are kept.
+  Delete moves stashed fingerprints back into the table when slots free up.
+- Lookup checks both buckets and the stash; Delete removes one copy of the fingerprint.
+- False-positive rate ≈ 2 * 4 * load / 2^f: two buckets of 4 slots, every occupied slot matches with probability 2^-f.
+
+When to use?
+- A membership filter in front of a cache or a store whose keys are also deleted.
+- When the target false-positive rate is low (below ~0.4%) and the capacity is known: it is smaller than a Bloom filter there.
+  Measured by the benchmarks in cuckoo_test.go at a load of 95% (62 259 keys):
+  f=12 — 12.6 bits/key at 0.15% (Bloom: 13.5 bits for the same rate); f=16 — 16.9 bits/key at 0.006%
+  (Bloom at 0.01%: 19.2 bits); f=8 — 8.4 bits/key at 2.8% (Bloom: 7.4 bits, smaller).
+- Do not insert the same key more than 8 times: its two buckets have only 8 slots (the filter is a multiset).
+  Delete only keys that were inserted, otherwise another key sharing the fingerprint loses its entry.
+
+How does it work?
+- The number of buckets is a power of two, so XOR keeps the alternate bucket in range.
+- The slots are packed into a bit array of f bits each (WithFingerprintBits, 4..32, 16 by default),
+  so the memory is exactly buckets * 4 * f bits plus the stash.
+- The table is sized for the given capacity at a load factor of 95%, the usual limit of a 4-way cuckoo table.
+  The bucket count is rounded up to a power of two, so the real load is between about 48% and 95%,
+  and a filter that is only half full takes twice the bits per key: pick capacity close to 0.95 * 4 * 2^n
+  (e.g. 62 259, 124 518, ...) when the memory matters, and compare with Bloom filters at the real load.
+
+### Complexity
+
+| Operation | Time (O) | Space (O) |
+|:---|:---:|:---:|
+| Insert | O(1) amortized, O(max kicks) worst | O(1) |
+| Lookup | O(1) | O(1) |
+| Delete | O(1) + O(stash) | O(1) |
+| Memory | — | O(n * f) bits |
+
+Examples of using Cuckoo filter:
+See the example.go file.
+*/
+
+package cuckoo
+
+import (
+	"math/bits"
+	"math/rand/v2"
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
+)
+
+const (
+	bucketSize             = 4
+	maxLoadFactor          = 0.95
+	defaultFingerprintBits = 16
+	defaultMaxKicks        = 500
+	defaultStashSize       = 8
+)
+
+// stashed - a fingerprint that did not fit into the table, with one of its two buckets
+type stashed struct {
+	fp     uint32
+	bucket uint64
+}
+
+// Filter - a cuckoo filter, safe for concurrent use
+type Filter[K any] struct {
+	slots    []uint64 // The bit array of buckets * 4 fingerprints, f bits each; 0 is an empty slot
+	buckets  uint64   // Number of buckets, a power of two
+	fpBits   uint     // Fingerprint size f
+	maxKicks int
+	stash    []stashed
+	stashCap int
+	count    uint64
+	hasher   hasher.Hasher[K]
+	rng      *rand.Rand // Picks the victims of kick-outs
+	mu       *sync.RWMutex
+}
+
+// Option - configures a cuckoo filter
+type Option[K any] func(*Filter[K])
+
+// WithFingerprintBits - sets the fingerprint size in bits, 4..32 (16 by default).
+// Every extra bit halves the false-positive rate and adds one bit per slot.
+func WithFingerprintBits[K any](n int) Option[K] {
+	return func(f *Filter[K]) {
+		f.fpBits = uint(min(max(n, 4), 32))
+	}
+}
+
+// WithMaxKicks - sets how many fingerprints one Insert may relocate before it uses the stash (500 by default)
+func WithMaxKicks[K any](n int) Option[K] {
+	return func(f *Filter[K]) {
+		if n >= 0 {
+			f.maxKicks = n
+		}
+	}
+}
+
+// WithStashSize - sets how many homeless fingerprints are kept outside the table (8 by default)
+func WithStashSize[K any](n int) Option[K] {
+	return func(f *Filter[K]) {
+		if n >= 0 {
+			f.stashCap = n
+		}
+	}
+}
+
+// WithHasher - sets the hash function of the keys (xxHash64 with seed 0 by default)
+func WithHasher[K any](h hasher.Hasher[K]) Option[K] {
+	return func(f *Filter[K]) {
+		if h != nil {
+			f.hasher = h
+		}
+	}
+}
+
+// New - creates a filter for capacity keys (the table is sized for a load factor of at most 95%,
+// the bucket count is rounded up to a power of two)
+func New[K any](capacity int, opts ...Option[K]) *Filter[K] {
+	f := &Filter[K]{
+		fpBits:   defaultFingerprintBits,
+		maxKicks: defaultMaxKicks,
+		stashCap: defaultStashSize,
+		hasher:   hasher.NewXXHash64WithSeed[K](0),
+		rng:      rand.New(rand.NewPCG(1, 2)),
+		mu:       &sync.RWMutex{},
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	capacity = max(capacity, 1)
+	f.buckets = nextPowerOfTwo(uint64(capacity+bucketSize-1) / bucketSize)
+	if float64(capacity) > float64(f.buckets*bucketSize)*maxLoadFactor {
+		f.buckets *= 2
+	}
+	f.slots = make([]uint64, (f.buckets*bucketSize*uint64(f.fpBits)+63)/64)
+
+	return f
+}
+
+// Insert - adds a key. Returns false if the filter is full: the kick-outs did not free a slot
+// and the stash has no room left (the filter is not changed then).
+func (f *Filter[K]) Insert(key K) bool {
+	fp, i1, i2 := f.locate(key)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.put(i1, fp) || f.put(i2, fp) {
+		f.count++
+		return true
+	}
+
+	type kick struct {
+		slot   uint64
+		victim uint32
+	}
+	var path []kick
+
+	i := i1
+	if f.rng.IntN(2) == 1 {
+		i = i2
+	}
+	for range f.maxKicks {
+		// Swap the fingerprint with a random victim and move the victim to its alternate bucket
+		slot := i*bucketSize + uint64(f.rng.IntN(bucketSize))
+		victim := f.get(slot)
+		f.set(slot, fp)
+		path = append(path, kick{slot: slot, victim: victim})
+
+		fp = victim
+		i = f.alternate(i, fp)
+		if f.put(i, fp) {
+			f.count++
+			return true
+		}
+	}
+
+	// The chain did not end: the last homeless fingerprint goes to the stash.
+	// With a full stash the kick-outs are undone, so no key that is already in the filter is lost.
+	if len(f.stash) >= f.stashCap {
+		for j := len(path) - 1; j >= 0; j-- {
+			f.set(path[j].slot, path[j].victim)
+		}
+		return false
+	}
+
+	f.stash = append(f.stash, stashed{fp: fp, bucket: i})
+	f.count++
+	return true
+}
+
+// Lookup - reports whether the key may be in the filter; false means it is definitely not there
+func (f *Filter[K]) Lookup(key K) bool {
+	fp, i1, i2 := f.locate(key)
+
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	if f.find(i1, fp) >= 0 || f.find(i2, fp) >= 0 {
+		return true
+	}
+	return f.findStashed(fp, i1, i2) >= 0
+}
+
+// Delete - removes one copy of a key. Returns false if the key is definitely not in the filter.
+// Only keys that were inserted may be deleted (see the package comment).
+func (f *Filter[K]) Delete(key K) bool {
+	fp, i1, i2 := f.locate(key)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, i := range [2]uint64{i1, i2} {
+		if s := f.find(i, fp); s >= 0 {
+			f.set(i*bucketSize+uint64(s), 0)
+			f.count--
+			f.unstash()
+			return true
+		}
+	}
+
+	if s := f.findStashed(fp, i1, i2); s >= 0 {
+		f.stash = append(f.stash[:s], f.stash[s+1:]...)
+		f.count--
+		return true
+	}
+	return false
+}
+
+// Count - returns the number of keys in the filter
+func (f *Filter[K]) Count() uint64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.count
+}
+
+// LoadFactor - returns the share of occupied slots in the table (stashed fingerprints are not counted)
+func (f *Filter[K]) LoadFactor() float64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return float64(f.count-uint64(len(f.stash))) / float64(f.buckets*bucketSize)
+}
+
+// Stashed - returns the number of fingerprints kept in the stash
+func (f *Filter[K]) Stashed() int {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return len(f.stash)
+}
+
+// Cap - returns the number of slots in the table
+func (f *Filter[K]) Cap() uint64 {
+	return f.buckets * bucketSize
+}
+
+// FingerprintBits - returns the fingerprint size in bits
+func (f *Filter[K]) FingerprintBits() int {
+	return int(f.fpBits)
+}
+
+// SizeBits - returns the memory taken by the fingerprints: the table plus the stash
+func (f *Filter[K]) SizeBits() uint64 {
+	return uint64(len(f.slots))*64 + uint64(f.stashCap)*(32+64)
+}
+
+// Reset - removes all keys
+func (f *Filter[K]) Reset() {
+	f.mu.Lock()
+	clear(f.slots)
+	f.stash = nil
+	f.count = 0
+	f.mu.Unlock()
+}
+
+// locate - returns the fingerprint and both buckets of a key
+func (f *Filter[K]) locate(key K) (fp uint32, i1, i2 uint64) {
+	h := f.hasher.Hash(key)
+
+	// The low bits pick the bucket, the high bits give the fingerprint, so they are independent
+	fp = uint32(h>>32) & (1<<f.fpBits - 1)
+	if fp == 0 {
+		fp = 1 // 0 marks an empty slot
+	}
+	i1 = h & (f.buckets - 1)
+	return fp, i1, f.alternate(i1, fp)
+}
+
+// alternate - returns the other bucket of a fingerprint: i XOR hash(fp)
+func (f *Filter[K]) alternate(i uint64, fp uint32) uint64 {
+	return (i ^ uint64(fp)*0x5bd1e995) & (f.buckets - 1)
+}
+
+// put - stores a fingerprint in a free slot of a bucket (f.mu must be held)
+func (f *Filter[K]) put(i uint64, fp uint32) bool {
+	for s := range uint64(bucketSize) {
+		if f.get(i*bucketSize+s) == 0 {
+			f.set(i*bucketSize+s, fp)
+			return true
+		}
+	}
+	return false
+}
+
+// find - returns the slot of a fingerprint in a bucket or -1 (f.mu must be held)
+func (f *Filter[K]) find(i uint64, fp uint32) int {
+	for s := range bucketSize {
+		if f.get(i*bucketSize+uint64(s)) == fp {
+			return s
+		}
+	}
+	return -1
+}
+
+// findStashed - returns the stash index of a fingerprint that belongs to one of the buckets or -1 (f.mu must be held)
+func (f *Filter[K]) findStashed(fp uint32, i1, i2 uint64) int {
+	for s, e := range f.stash {
+		if e.fp == fp && (e.bucket == i1 || e.bucket == i2) {
+			return s
+		}
+	}
+	return -1
+}
+
+// unstash - moves stashed fingerprints back into the table if their buckets have free slots (f.mu must be held)
+func (f *Filter[K]) unstash() {
+	kept := f.stash[:0]
+	for _, e := range f.stash {
+		if !f.put(e.bucket, e.fp) && !f.put(f.alternate(e.bucket, e.fp), e.fp) {
+			kept = append(kept, e)
+		}
+	}
+	f.stash = kept
+}
+
+// get - reads the fingerprint of a slot from the bit array
+func (f *Filter[K]) get(slot uint64) uint32 {
+	pos := slot * uint64(f.fpBits)
+	word, off := pos/64, pos%64
+
+	v := f.slots[word] >> off
+	if off+uint64(f.fpBits) > 64 {
+		v |= f.slots[word+1] << (64 - off) // The slot crosses a word boundary
+	}
+	return uint32(v) & (1<<f.fpBits - 1)
+}
+
+// set - writes the fingerprint of a slot into the bit array
+func (f *Filter[K]) set(slot uint64, fp uint32) {
+	pos := slot * uint64(f.fpBits)
+	word, off := pos/64, pos%64
+	mask := uint64(1)<<f.fpBits - 1
+
+	f.slots[word] = f.slots[word]&^(mask<<off) | uint64(fp)<<off
+	if off+uint64(f.fpBits) > 64 {
+		rest := 64 - off
+		f.slots[word+1] = f.slots[word+1]&^(mask>>rest) | uint64(fp)>>rest
+	}
+}
+
+// nextPowerOfTwo - returns the smallest power of two >= n
+func nextPowerOfTwo(n uint64) uint64 {
+	if n <= 1 {
+		return 1
+	}
+	return 1 << bits.Len64(n-1)
+}
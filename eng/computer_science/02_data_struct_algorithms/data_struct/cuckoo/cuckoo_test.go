@@ -0,0 +1,127 @@
+package cuckoo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/bloom"
+)
+
+// benchKeys - the number of keys a benchmarked filter is sized for: 95% of a table of 2^14 buckets.
+// The bucket count is a power of two, so for most other counts the cuckoo table is less full and takes more bits per key.
+const benchKeys = 95 * (1 << 14) * bucketSize / 100
+
+// filter - the operations shared by the cuckoo and the Bloom filters
+type filter struct {
+	insert func(key string) bool
+	lookup func(key string) bool
+	bits   func() uint64
+	reset  func()
+}
+
+// filters - the benchmarked filters, sized for n keys with comparable false-positive rates
+var filters = []struct {
+	name string
+	make func(n int) filter
+}{
+	{"cuckoo/f=8", newCuckoo(8)},
+	{"cuckoo/f=12", newCuckoo(12)},
+	{"cuckoo/f=16", newCuckoo(16)},
+	{"bloom/p=0.01", newBloom(0.01)},
+	{"bloom/p=0.001", newBloom(0.001)},
+	{"bloom/p=0.0001", newBloom(0.0001)},
+}
+
+func newCuckoo(fpBits int) func(n int) filter {
+	return func(n int) filter {
+		f := New[string](n, WithFingerprintBits[string](fpBits))
+		return filter{insert: f.Insert, lookup: f.Lookup, bits: f.SizeBits, reset: f.Reset}
+	}
+}
+
+func newBloom(p float64) func(n int) filter {
+	return func(n int) filter {
+		f := bloom.New[string](n, p)
+		return filter{
+			insert: func(key string) bool { f.Add(key); return true },
+			lookup: f.Contains,
+			bits:   f.Cap,
+			reset:  f.Reset,
+		}
+	}
+}
+
+// keys - returns n distinct keys with the prefix
+func keys(prefix string, n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("%s:%d", prefix, i)
+	}
+	return out
+}
+
+// reportSpace - reports the false-positive rate (measured with never-added keys) and the bits per key of a full filter
+func reportSpace(b *testing.B, f filter, absent []string) {
+	positives := 0
+	for _, key := range absent {
+		if f.lookup(key) {
+			positives++
+		}
+	}
+	b.ReportMetric(float64(positives)/float64(len(absent)), "fp-rate")
+	b.ReportMetric(float64(f.bits())/benchKeys, "bits/key")
+}
+
+func BenchmarkInsert(b *testing.B) {
+	added, absent := keys("key", benchKeys), keys("absent", benchKeys)
+
+	for _, fc := range filters {
+		b.Run(fc.name, func(b *testing.B) {
+			f := fc.make(benchKeys)
+
+			b.ResetTimer()
+			for i := range b.N {
+				// The filter is refilled from empty every benchKeys inserts, so it never overflows
+				if i%benchKeys == 0 && i > 0 {
+					f.reset()
+				}
+				if !f.insert(added[i%benchKeys]) {
+					b.Fatalf("insert %d failed", i%benchKeys)
+				}
+			}
+			b.StopTimer()
+
+			f.reset()
+			for _, key := range added {
+				f.insert(key)
+			}
+			reportSpace(b, f, absent)
+		})
+	}
+}
+
+func BenchmarkLookup(b *testing.B) {
+	added, absent := keys("key", benchKeys), keys("absent", benchKeys)
+
+	for _, fc := range filters {
+		b.Run(fc.name, func(b *testing.B) {
+			f := fc.make(benchKeys)
+			for _, key := range added {
+				f.insert(key)
+			}
+
+			// Half of the lookups are for added keys, half for never-added ones
+			b.ResetTimer()
+			for i := range b.N {
+				if i%2 == 0 {
+					f.lookup(added[i/2%benchKeys])
+				} else {
+					f.lookup(absent[i/2%benchKeys])
+				}
+			}
+			b.StopTimer()
+
+			reportSpace(b, f, absent)
+		})
+	}
+}
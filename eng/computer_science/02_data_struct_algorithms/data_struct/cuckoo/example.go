@@ -0,0 +1,102 @@
+package cuckoo
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/bloom"
+)
+
+// Example demonstrates the use of a cuckoo filter and compares it with Bloom filters
+func Example() {
+	// 1. Insert, Lookup and Delete
+	f := New[string](1000, WithFingerprintBits[string](12))
+	for _, user := range []string{"alice", "bob", "carol"} {
+		f.Insert(user)
+	}
+	fmt.Printf("alice %t, dave %t, count %d\n", f.Lookup("alice"), f.Lookup("dave"), f.Count())
+	fmt.Printf("Delete(bob) %t, bob %t, Delete(dave) %t, count %d\n", f.Delete("bob"), f.Lookup("bob"), f.Delete("dave"), f.Count())
+
+	// 2. Fill a small filter until it is full: the last fingerprints go to the stash
+	small := New[int](1000, WithStashSize[int](4), WithMaxKicks[int](100))
+	inserted := 0
+	for small.Insert(inserted) {
+		inserted++
+	}
+	fmt.Printf("Small filter: %d slots, %d keys inserted, load %.3f, %d stashed\n",
+		small.Cap(), inserted, small.LoadFactor(), small.Stashed())
+
+	falseNegatives := 0
+	for i := range inserted {
+		if !small.Lookup(i) {
+			falseNegatives++
+		}
+	}
+	fmt.Printf("False negatives after the failed Insert: %d\n", falseNegatives)
+
+	// Deleting keys frees slots, and the stashed fingerprints move back into the table
+	for i := range 100 {
+		small.Delete(i)
+	}
+	fmt.Printf("After 100 deletes: count %d, %d stashed, Insert %t\n", small.Count(), small.Stashed(), small.Insert(-1))
+
+	// 3. Cuckoo versus Bloom: false-positive rate and space per key
+	CompareWithBloom(60000, 600000)
+}
+
+// CompareWithBloom - fills Bloom filters (data_struct/bloom) and cuckoo filters with n keys,
+// then probes them with never-added keys and prints the measured false-positive rate
+// and the bits per key of every filter (the speed is measured by the benchmarks in cuckoo_test.go)
+func CompareWithBloom(n, probes int) {
+	type candidate struct {
+		name   string
+		insert func(key string) bool
+		lookup func(key string) bool
+		bits   func() uint64
+		load   func() float64 // Share of the filled slots, nil for Bloom filters
+	}
+
+	var candidates []candidate
+	for _, p := range []float64{0.01, 0.001, 0.0001} {
+		f := bloom.New[string](n, p)
+		candidates = append(candidates, candidate{
+			name:   fmt.Sprintf("bloom p=%g (k=%d)", p, f.K()),
+			insert: func(key string) bool { f.Add(key); return true },
+			lookup: f.Contains,
+			bits:   f.Cap,
+		})
+	}
+	for _, fpBits := range []int{8, 12, 16} {
+		f := New[string](n, WithFingerprintBits[string](fpBits))
+		candidates = append(candidates, candidate{
+			name:   fmt.Sprintf("cuckoo f=%d", fpBits),
+			insert: f.Insert,
+			lookup: f.Lookup,
+			bits:   f.SizeBits,
+			load:   f.LoadFactor,
+		})
+	}
+
+	for _, c := range candidates {
+		failed := 0
+		for i := range n {
+			if !c.insert("key:" + strconv.Itoa(i)) {
+				failed++
+			}
+		}
+
+		positives := 0
+		for i := range probes {
+			if c.lookup("absent:" + strconv.Itoa(i)) {
+				positives++
+			}
+		}
+
+		fmt.Printf("%-22s false positives %.5f, %5.2f bits/key, failed inserts %d",
+			c.name, float64(positives)/float64(probes), float64(c.bits())/float64(n), failed)
+		if c.load != nil {
+			fmt.Printf(", load factor %.3f", c.load())
+		}
+		fmt.Println()
+	}
+}
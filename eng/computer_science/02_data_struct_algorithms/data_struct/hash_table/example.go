@@ -2,7 +2,11 @@ package hash_table
 
 import (
 	"fmt"
+	"math/rand"
 	"sort"
+	"time"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
 )
 
 // Example demonstrates the use of a hash table with various examples
@@ -35,6 +39,104 @@ func Example() {
 
 	hasDup = ContainsDuplicate([]int{1, 2, 3, 4})
 	fmt.Printf("Duplicates in [1,2,3,4]: %t\n", hasDup)
+
+	// Example 6: Sudoku validation
+	board := [][]byte{
+		[]byte("53..7...."),
+		[]byte("6..195..."),
+		[]byte(".98....6."),
+		[]byte("8...6...3"),
+		[]byte("4..8.3..1"),
+		[]byte("7...2...6"),
+		[]byte(".6....28."),
+		[]byte("...419..5"),
+		[]byte("....8..79"),
+	}
+	fmt.Printf("Is the sudoku board valid: %t\n", IsValidSudoku(board))
+
+	// Example 7: Our own hash table with incremental rehashing
+	// (keys may come from users, so SipHash with a random key is used)
+	ht := NewWithHasher[string, int](hasher.NewSipHash[string](), WithCapacity(2), WithLoadFactor(0.75))
+	for i, word := range []string{"one", "two", "three", "four", "five"} {
+		ht.Put(word, i+1) // Growth starts here, buckets are moved by later calls
+	}
+	ht.Delete("two")
+
+	three, _ := ht.Get("three")
+	fmt.Printf("HashTable: len=%d, three=%d\n", ht.Len(), three)
+
+	// Example 8: Chaining vs Robin Hood on the same workloads
+	CompareTables(200_000, 1_000)
+}
+
+// Map - operations shared by HashTable and RobinHoodMap
+type Map[K comparable, V any] interface {
+	Get(key K) (V, bool)
+	Put(key K, value V)
+	Delete(key K) bool
+	Len() int
+	Range(fn func(key K, value V) bool)
+}
+
+// CountFrequencyWith - the CountFrequency workload on top of any Map
+func CountFrequencyWith(m Map[int, int], nums []int) Map[int, int] {
+	for _, num := range nums {
+		count, _ := m.Get(num)
+		m.Put(num, count+1)
+	}
+
+	return m
+}
+
+// ContainsNearbyDuplicateWith - the ContainsNearbyDuplicate workload on top of any Map
+func ContainsNearbyDuplicateWith(m Map[int, int], nums []int, k int) bool {
+	for i, num := range nums {
+		if prevIndex, exists := m.Get(num); exists && i-prevIndex <= k {
+			return true
+		}
+		m.Put(num, i)
+	}
+
+	return false
+}
+
+// CompareTables - runs the CountFrequency and ContainsNearbyDuplicate workloads
+// on the chaining table and on the Robin Hood map and prints the timings.
+// n is the number of elements, distinct is the number of different values among them.
+func CompareTables(n, distinct int) {
+	rng := rand.New(rand.NewSource(1))
+
+	freqNums := make([]int, n)
+	for i := range freqNums {
+		freqNums[i] = rng.Intn(distinct)
+	}
+
+	// All values are unique, so ContainsNearbyDuplicate has to scan the whole input
+	uniqueNums := rng.Perm(n)
+
+	workloads := []struct {
+		name string
+		run  func(m Map[int, int])
+	}{
+		{"CountFrequency", func(m Map[int, int]) { CountFrequencyWith(m, freqNums) }},
+		{"ContainsNearbyDuplicate", func(m Map[int, int]) { ContainsNearbyDuplicateWith(m, uniqueNums, n) }},
+	}
+
+	for _, w := range workloads {
+		chaining := New[int, int]()
+		start := time.Now()
+		w.run(chaining)
+		chainingTime := time.Since(start)
+
+		robinHood := NewRobinHoodMap[int, int]()
+		start = time.Now()
+		w.run(robinHood)
+		robinHoodTime := time.Since(start)
+
+		stats := robinHood.Stats()
+		fmt.Printf("%s (%d entries): chaining %v, robin hood %v (max displacement %d, mean %.2f)\n",
+			w.name, robinHood.Len(), chainingTime, robinHoodTime, stats.MaxDisplacement, stats.MeanDisplacement)
+	}
 }
 
 // Problem 1: Find the difference between two strings
@@ -75,18 +177,18 @@ func ExtraLetter(a, b string) string {
 // Time complexity: O(n), space complexity: O(n)
 func TwoSum(data []int, target int) []int {
 	// Create a hash table to store values and their indices
-	cache := make(map[int]int)
+	cache := New[int, int](WithCapacity(len(data)))
 
 	for i, num := range data {
 		complement := target - num // The complement needed to reach the target
 
 		// Check if the complement is in the hash table
-		if index, ok := cache[complement]; ok {
+		if index, ok := cache.Get(complement); ok {
 			return []int{index, i} // Return indices of the two elements
 		}
 
 		// Save the current element and its index
-		cache[num] = i
+		cache.Put(num, i)
 	}
 
 	return []int{} // If no pair is found
@@ -99,21 +201,23 @@ func TwoSum(data []int, target int) []int {
 // Example: ["eat", "tea", "tan", "ate", "nat", "bat"] => [["bat"], ["nat", "tan"], ["ate", "eat", "tea"]]
 // Time complexity: O(n * m * log(m)), where n is the number of strings and m is the average string length
 func GroupAnagrams(strs []string) [][]string {
-	anagramGroups := make(map[string][]string)
+	anagramGroups := New[string, []string]()
 
 	for _, str := range strs {
 		// Sort the string's characters to get a common key for anagrams
 		sortedStr := sortString(str)
 
 		// Add the string to the group with the corresponding key
-		anagramGroups[sortedStr] = append(anagramGroups[sortedStr], str)
+		group, _ := anagramGroups.Get(sortedStr)
+		anagramGroups.Put(sortedStr, append(group, str))
 	}
 
-	// Convert the map to a slice of slices
-	result := make([][]string, 0, len(anagramGroups))
-	for _, group := range anagramGroups {
+	// Convert the hash table to a slice of slices
+	result := make([][]string, 0, anagramGroups.Len())
+	anagramGroups.Range(func(_ string, group []string) bool {
 		result = append(result, group)
-	}
+		return true
+	})
 
 	return result
 }
@@ -255,15 +359,15 @@ func ContainsNearbyDuplicate(nums []int, k int) bool {
 // Problem 10: Valid Sudoku
 // Determine if a 9x9 Sudoku board is valid. Only filled cells need to be validated.
 func IsValidSudoku(board [][]byte) bool {
-	rows := make([]map[byte]bool, 9)
-	cols := make([]map[byte]bool, 9)
-	boxes := make([]map[byte]bool, 9)
+	rows := make([]*HashTable[byte, bool], 9)
+	cols := make([]*HashTable[byte, bool], 9)
+	boxes := make([]*HashTable[byte, bool], 9)
 
-	// Initialize maps
+	// Initialize hash tables
 	for i := 0; i < 9; i++ {
-		rows[i] = make(map[byte]bool)
-		cols[i] = make(map[byte]bool)
-		boxes[i] = make(map[byte]bool)
+		rows[i] = New[byte, bool]()
+		cols[i] = New[byte, bool]()
+		boxes[i] = New[byte, bool]()
 	}
 
 	for i := 0; i < 9; i++ {
@@ -274,23 +378,23 @@ func IsValidSudoku(board [][]byte) bool {
 			}
 
 			// Row check
-			if rows[i][cell] {
+			if _, seen := rows[i].Get(cell); seen {
 				return false
 			}
-			rows[i][cell] = true
+			rows[i].Put(cell, true)
 
 			// Column check
-			if cols[j][cell] {
+			if _, seen := cols[j].Get(cell); seen {
 				return false
 			}
-			cols[j][cell] = true
+			cols[j].Put(cell, true)
 
 			// 3x3 square check
 			boxIndex := (i/3)*3 + j/3
-			if boxes[boxIndex][cell] {
+			if _, seen := boxes[boxIndex].Get(cell); seen {
 				return false
 			}
-			boxes[boxIndex][cell] = true
+			boxes[boxIndex].Put(cell, true)
 		}
 	}
 
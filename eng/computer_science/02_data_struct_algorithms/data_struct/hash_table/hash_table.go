@@ -55,8 +55,278 @@ In Go, hash tables are implemented as a built-in data structure — the map.
 - No guarantee of element order during iteration.
 - Not thread-safe (requires synchronization for concurrent access).
 
+HashTable[K, V] (our own implementation):
+
+The package also contains a hand-written hash table, so the examples do not depend only on the built-in map.
+- Collisions are resolved by separate chaining: every bucket is a singly linked list of entries.
+- The load factor (entries / buckets) is configurable; when it is exceeded the table doubles.
+- Resizing is incremental (the way Redis does it): the table keeps the old and the new bucket arrays
+  at the same time and every following Get/Put/Delete moves one bucket from the old array to the new one.
+  A single Put never has to rehash the whole table, so there are no long pauses on large tables.
+- While rehashing is in progress, lookups check both arrays; new entries always go into the new one.
+- The hash function is pluggable (see the hasher package). By default every table gets its own random seed,
+  so nobody can prepare a set of keys that all land in one bucket.
+
+| Operation | Average (O) | Worst (O) |
+|:---|:---:|:---:|
+| Put | O(1) | O(n) |
+| Get | O(1) | O(n) |
+| Delete | O(1) | O(n) |
+| Rehash step | O(1)* | O(1)* |
+| Range | O(n) | O(n) |
+
+*One bucket per step; the whole resize is spread over the next operations.
+
 Examples of using Hash Table:
 See the example.go file.
 */
 
 package hash_table
+
+import "github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
+
+const (
+	defaultCapacity   = 8   // Initial number of buckets
+	defaultLoadFactor = 1.0 // Average chain length that triggers growth
+	emptyVisits       = 10  // Max empty buckets skipped during one rehash step
+	noRehash          = -1  // rehashIdx value when no rehash is in progress
+)
+
+// entry - an element of a bucket chain
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	next  *entry[K, V]
+}
+
+// table - an array of buckets and the number of entries stored in it
+type table[K comparable, V any] struct {
+	buckets []*entry[K, V]
+	used    int
+}
+
+// HashTable - a hash table with separate chaining and incremental rehashing
+type HashTable[K comparable, V any] struct {
+	tables     [2]table[K, V] // tables[1] is used only while rehashing
+	rehashIdx  int            // Next bucket of tables[0] to move, or noRehash
+	loadFactor float64
+	hasher     hasher.Hasher[K]
+}
+
+// options - settings of a hash table
+type options struct {
+	capacity   int
+	loadFactor float64
+}
+
+// Option - configures a hash table
+type Option func(*options)
+
+// WithCapacity - sets the initial number of buckets
+func WithCapacity(capacity int) Option {
+	return func(o *options) {
+		if capacity > 0 {
+			o.capacity = capacity
+		}
+	}
+}
+
+// WithLoadFactor - sets the load factor after which the table grows
+func WithLoadFactor(loadFactor float64) Option {
+	return func(o *options) {
+		if loadFactor > 0 {
+			o.loadFactor = loadFactor
+		}
+	}
+}
+
+// New - creates an empty hash table with a randomly seeded runtime hash
+func New[K comparable, V any](opts ...Option) *HashTable[K, V] {
+	return NewWithHasher[K, V](hasher.NewMapHash[K](), opts...)
+}
+
+// NewWithHasher - creates an empty hash table that uses the given hash function
+func NewWithHasher[K comparable, V any](h hasher.Hasher[K], opts ...Option) *HashTable[K, V] {
+	o := options{
+		capacity:   defaultCapacity,
+		loadFactor: defaultLoadFactor,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	ht := &HashTable[K, V]{
+		rehashIdx:  noRehash,
+		loadFactor: o.loadFactor,
+		hasher:     h,
+	}
+	ht.tables[0].buckets = make([]*entry[K, V], o.capacity)
+
+	return ht
+}
+
+// Len - returns the number of stored entries
+func (ht *HashTable[K, V]) Len() int {
+	return ht.tables[0].used + ht.tables[1].used
+}
+
+// Get - returns the value for a key
+func (ht *HashTable[K, V]) Get(key K) (V, bool) {
+	ht.rehashStep()
+
+	if e := ht.find(key); e != nil {
+		return e.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Put - adds a new entry or updates the value of an existing one
+func (ht *HashTable[K, V]) Put(key K, value V) {
+	ht.rehashStep()
+
+	if e := ht.find(key); e != nil {
+		e.value = value
+		return
+	}
+
+	// New entries always go into the newest table
+	t := &ht.tables[0]
+	if ht.rehashing() {
+		t = &ht.tables[1]
+	}
+
+	idx := ht.index(key, len(t.buckets))
+	t.buckets[idx] = &entry[K, V]{key: key, value: value, next: t.buckets[idx]}
+	t.used++
+
+	ht.maybeGrow()
+}
+
+// Delete - removes an entry, reports whether it was present
+func (ht *HashTable[K, V]) Delete(key K) bool {
+	ht.rehashStep()
+
+	for i := range ht.tables {
+		t := &ht.tables[i]
+		if len(t.buckets) == 0 {
+			continue
+		}
+
+		idx := ht.index(key, len(t.buckets))
+		for prev, e := (*entry[K, V])(nil), t.buckets[idx]; e != nil; prev, e = e, e.next {
+			if e.key != key {
+				continue
+			}
+
+			// Unlink the entry from the chain
+			if prev == nil {
+				t.buckets[idx] = e.next
+			} else {
+				prev.next = e.next
+			}
+			t.used--
+			return true
+		}
+	}
+
+	return false
+}
+
+// Range - calls fn for every entry until fn returns false.
+// The order is not defined, just like for the built-in map.
+func (ht *HashTable[K, V]) Range(fn func(key K, value V) bool) {
+	for i := range ht.tables {
+		for _, e := range ht.tables[i].buckets {
+			for ; e != nil; e = e.next {
+				if !fn(e.key, e.value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// find - looks for an entry in both tables
+func (ht *HashTable[K, V]) find(key K) *entry[K, V] {
+	for i := range ht.tables {
+		t := &ht.tables[i]
+		if len(t.buckets) == 0 {
+			continue
+		}
+
+		for e := t.buckets[ht.index(key, len(t.buckets))]; e != nil; e = e.next {
+			if e.key == key {
+				return e
+			}
+		}
+	}
+
+	return nil
+}
+
+// index - converts a key into a bucket index
+func (ht *HashTable[K, V]) index(key K, size int) int {
+	return int(ht.hasher.Hash(key) % uint64(size))
+}
+
+// rehashing - reports whether an incremental rehash is in progress
+func (ht *HashTable[K, V]) rehashing() bool {
+	return ht.rehashIdx != noRehash
+}
+
+// maybeGrow - starts a rehash into a table twice as large once the load factor is exceeded
+func (ht *HashTable[K, V]) maybeGrow() {
+	if ht.rehashing() {
+		return
+	}
+
+	t := &ht.tables[0]
+	if float64(t.used)/float64(len(t.buckets)) <= ht.loadFactor {
+		return
+	}
+
+	ht.tables[1] = table[K, V]{buckets: make([]*entry[K, V], len(t.buckets)*2)}
+	ht.rehashIdx = 0
+}
+
+// rehashStep - moves one non-empty bucket from the old table to the new one
+func (ht *HashTable[K, V]) rehashStep() {
+	if !ht.rehashing() {
+		return
+	}
+
+	src, dst := &ht.tables[0], &ht.tables[1]
+
+	// Skip a limited number of empty buckets so that one step stays O(1)
+	for visits := 0; ht.rehashIdx < len(src.buckets) && src.buckets[ht.rehashIdx] == nil; visits++ {
+		if visits == emptyVisits {
+			return
+		}
+		ht.rehashIdx++
+	}
+
+	if ht.rehashIdx < len(src.buckets) {
+		for e := src.buckets[ht.rehashIdx]; e != nil; {
+			next := e.next
+
+			idx := ht.index(e.key, len(dst.buckets))
+			e.next = dst.buckets[idx]
+			dst.buckets[idx] = e
+			src.used--
+			dst.used++
+
+			e = next
+		}
+		src.buckets[ht.rehashIdx] = nil
+		ht.rehashIdx++
+	}
+
+	// All buckets are moved: the new table becomes the main one
+	if ht.rehashIdx == len(src.buckets) {
+		ht.tables[0] = *dst
+		ht.tables[1] = table[K, V]{}
+		ht.rehashIdx = noRehash
+	}
+}
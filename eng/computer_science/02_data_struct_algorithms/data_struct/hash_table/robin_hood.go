@@ -0,0 +1,240 @@
+/*
+Robin Hood Hashing (open addressing)
+
+What is it?
+A hash table without chains: all entries live directly in one array of slots. When the home slot of a key
+is taken, the next slots are probed one by one (linear probing) until a free one is found.
+
+What's the point of "Robin Hood"?
+- Every entry remembers its displacement — how far it sits from its home slot.
+- During insertion, if the new entry has travelled further than the entry in the current slot,
+  they swap places: the "poor" entry takes the slot from the "rich" one, and the rich one continues probing.
+- As a result, displacements stay small and close to each other, so lookups are predictable.
+
+Deletion without tombstones (backward shift):
+- Classic open addressing marks deleted slots with a tombstone, and tombstones slow down later lookups.
+- Here, after removing an entry, the following entries of the same cluster are shifted one slot back
+  (each of them gets one step closer to its home slot) until an empty slot or an entry at its home slot is met.
+
+Why compare it with chaining?
+- All entries are stored in one contiguous array, so probing touches neighbouring memory (cache friendly).
+- Chaining follows pointers to separately allocated entries, which is friendlier to high load factors.
+
+### Complexity
+
+| Operation | Average (O) | Worst (O) |
+|:---|:---:|:---:|
+| Put | O(1) | O(n) |
+| Get | O(1) | O(n) |
+| Delete | O(1) | O(n) |
+| Grow | O(n) | O(n) |
+*/
+
+package hash_table
+
+import "github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
+
+const (
+	defaultRobinHoodCapacity   = 8    // Initial number of slots (power of two)
+	defaultRobinHoodLoadFactor = 0.85 // Share of occupied slots that triggers growth
+)
+
+// slot - a cell of the open-addressing array
+type slot[K comparable, V any] struct {
+	key   K
+	value V
+	dist  int  // Displacement from the home slot
+	used  bool // Whether the slot holds an entry
+}
+
+// RobinHoodMap - an open-addressing hash map with Robin Hood linear probing
+type RobinHoodMap[K comparable, V any] struct {
+	slots      []slot[K, V]
+	mask       uint64 // len(slots)-1, the size is always a power of two
+	count      int
+	loadFactor float64
+	hasher     hasher.Hasher[K]
+}
+
+// ProbeStats - displacement statistics of a RobinHoodMap
+type ProbeStats struct {
+	MaxDisplacement  int     // Longest distance of an entry from its home slot
+	MeanDisplacement float64 // Average distance of an entry from its home slot
+}
+
+// NewRobinHoodMap - creates an empty Robin Hood map with a randomly seeded runtime hash.
+// Accepts the same options as New; the load factor must be below 1.
+func NewRobinHoodMap[K comparable, V any](opts ...Option) *RobinHoodMap[K, V] {
+	return NewRobinHoodMapWithHasher[K, V](hasher.NewMapHash[K](), opts...)
+}
+
+// NewRobinHoodMapWithHasher - creates an empty Robin Hood map that uses the given hash function
+func NewRobinHoodMapWithHasher[K comparable, V any](h hasher.Hasher[K], opts ...Option) *RobinHoodMap[K, V] {
+	o := options{
+		capacity:   defaultRobinHoodCapacity,
+		loadFactor: defaultRobinHoodLoadFactor,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.loadFactor >= 1 {
+		o.loadFactor = defaultRobinHoodLoadFactor
+	}
+
+	size := 1
+	for size < o.capacity {
+		size <<= 1
+	}
+
+	return &RobinHoodMap[K, V]{
+		slots:      make([]slot[K, V], size),
+		mask:       uint64(size - 1),
+		loadFactor: o.loadFactor,
+		hasher:     h,
+	}
+}
+
+// Len - returns the number of stored entries
+func (m *RobinHoodMap[K, V]) Len() int {
+	return m.count
+}
+
+// Get - returns the value for a key
+func (m *RobinHoodMap[K, V]) Get(key K) (V, bool) {
+	if idx, ok := m.find(key); ok {
+		return m.slots[idx].value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Put - adds a new entry or updates the value of an existing one
+func (m *RobinHoodMap[K, V]) Put(key K, value V) {
+	if idx, ok := m.find(key); ok {
+		m.slots[idx].value = value
+		return
+	}
+
+	if float64(m.count+1) > float64(len(m.slots))*m.loadFactor {
+		m.grow()
+	}
+
+	m.insert(slot[K, V]{key: key, value: value, used: true})
+	m.count++
+}
+
+// Delete - removes an entry using backward shift, reports whether it was present
+func (m *RobinHoodMap[K, V]) Delete(key K) bool {
+	idx, ok := m.find(key)
+	if !ok {
+		return false
+	}
+
+	// Pull the rest of the cluster one slot back until an empty slot
+	// or an entry that already sits at its home slot
+	for {
+		next := (idx + 1) & m.mask
+		if !m.slots[next].used || m.slots[next].dist == 0 {
+			break
+		}
+
+		m.slots[idx] = m.slots[next]
+		m.slots[idx].dist--
+		idx = next
+	}
+
+	m.slots[idx] = slot[K, V]{}
+	m.count--
+	return true
+}
+
+// Range - calls fn for every entry until fn returns false
+func (m *RobinHoodMap[K, V]) Range(fn func(key K, value V) bool) {
+	for i := range m.slots {
+		if m.slots[i].used && !fn(m.slots[i].key, m.slots[i].value) {
+			return
+		}
+	}
+}
+
+// Stats - returns the probe length statistics
+func (m *RobinHoodMap[K, V]) Stats() ProbeStats {
+	var stats ProbeStats
+	total := 0
+
+	for i := range m.slots {
+		if !m.slots[i].used {
+			continue
+		}
+
+		total += m.slots[i].dist
+		stats.MaxDisplacement = max(stats.MaxDisplacement, m.slots[i].dist)
+	}
+
+	if m.count > 0 {
+		stats.MeanDisplacement = float64(total) / float64(m.count)
+	}
+
+	return stats
+}
+
+// home - returns the home slot of a key
+func (m *RobinHoodMap[K, V]) home(key K) uint64 {
+	return m.hasher.Hash(key) & m.mask
+}
+
+// find - returns the slot index of a key
+func (m *RobinHoodMap[K, V]) find(key K) (uint64, bool) {
+	idx := m.home(key)
+
+	for dist := 0; ; dist++ {
+		s := &m.slots[idx]
+
+		// An empty slot or a "richer" entry means the key would have been placed earlier
+		if !s.used || s.dist < dist {
+			return 0, false
+		}
+		if s.key == key {
+			return idx, true
+		}
+
+		idx = (idx + 1) & m.mask
+	}
+}
+
+// insert - places an entry that is known to be absent
+func (m *RobinHoodMap[K, V]) insert(e slot[K, V]) {
+	idx := m.home(e.key)
+	e.dist = 0
+
+	for {
+		s := &m.slots[idx]
+		if !s.used {
+			*s = e
+			return
+		}
+
+		// Robin Hood: the entry that travelled further takes the slot
+		if s.dist < e.dist {
+			*s, e = e, *s
+		}
+
+		idx = (idx + 1) & m.mask
+		e.dist++
+	}
+}
+
+// grow - doubles the array and reinserts all entries
+func (m *RobinHoodMap[K, V]) grow() {
+	old := m.slots
+
+	m.slots = make([]slot[K, V], len(old)*2)
+	m.mask = uint64(len(m.slots) - 1)
+
+	for i := range old {
+		if old[i].used {
+			m.insert(old[i])
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package hasher
+
+import "fmt"
+
+// Example demonstrates the use of pluggable hashers
+func Example() {
+	key := "user:42"
+
+	// Fixed seeds give the same result on every run
+	fmt.Printf("FNV-1a:    %016x\n", NewFNV1aWithSeed[string](0).Hash(key))
+	fmt.Printf("xxHash64:  %016x\n", NewXXHash64WithSeed[string](0).Hash(key))
+	fmt.Printf("SipHash:   %016x\n", NewSipHashWithKey[string](0x0706050403020100, 0x0f0e0d0c0b0a0908).Hash(key))
+
+	// Random seeds: two instances disagree, so collisions cannot be prepared in advance
+	a, b := Default[string](), Default[string]()
+	fmt.Printf("Two random SipHash instances agree: %t\n", a.Hash(key) == b.Hash(key))
+
+	// Any comparable key type can be hashed
+	ids := NewXXHash64[int]()
+	fmt.Printf("Shard of id 1001 out of 8: %d\n", ids.Hash(1001)%8)
+}
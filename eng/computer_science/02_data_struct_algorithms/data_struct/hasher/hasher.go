@@ -0,0 +1,244 @@
+/*
+Hash Functions (pluggable and seeded)
+
+What is it?
+A hash function turns a key of any size into a fixed-size number. Hash tables, sharded caches and Bloom filters
+use this number to pick a bucket, a shard or a bit. This package hides the concrete function behind a small
+Hasher[K] interface, so every hash-based structure can be given the function that suits it.
+
+Why is it needed?
+- Different tasks need different trade-offs: raw speed, good distribution, or resistance to attacks.
+- A hard-coded, unseeded hash is predictable. An attacker who knows it can craft many keys with the same hash
+  and push all of them into one bucket or one shard (HashDoS): every operation degrades to O(n).
+- With a random per-instance seed, the attacker cannot know in advance which keys collide.
+
+Available functions:
+- FNV-1a (64 bit) — very simple and fast on short keys. The seed only changes the distribution;
+  FNV is NOT a keyed function and does not protect against a determined attacker.
+- xxHash64 — very fast on long keys, excellent distribution, supports a seed. Not cryptographic either.
+- MapHash — the hash used by Go's built-in map (hash/maphash), randomly seeded per instance.
+  Fast for any comparable key because it does not encode the key into bytes first.
+- SipHash-2-4 — a keyed pseudo-random function with a 128-bit secret key. It was designed exactly against
+  HashDoS and is used by Python, Rust and Redis for their hash tables. Slower than the others, but safe.
+
+When to use what?
+- Keys come from users (HTTP parameters, headers, JSON) — SipHash with a random key (the Default).
+- Keys are internal and trusted, speed matters — xxHash64 or FNV-1a.
+- Results must be stable between runs (e.g. saved to disk) — any function with a fixed seed.
+
+### Complexity
+
+| Function | Time (O) | Keyed | HashDoS resistant |
+|:---|:---:|:---:|:---:|
+| FNV-1a | O(m) | no (seed only) | no |
+| MapHash | O(m) | yes (random seed) | yes** |
+| xxHash64 | O(m) | no (seed only) | no |
+| SipHash-2-4 | O(m) | yes (128 bit) | yes |
+
+*m — key length in bytes.
+**The seed cannot be read or chosen, so it is safe for maps, but its values must never leave the process.
+
+Examples of using hashers:
+See the example.go file.
+*/
+
+package hasher
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"hash/maphash"
+	"math"
+)
+
+// Hasher - turns a key into a 64-bit hash
+type Hasher[K any] interface {
+	Hash(key K) uint64
+}
+
+// Default - returns the recommended hasher: SipHash-2-4 with a random key
+func Default[K any]() Hasher[K] {
+	return NewSipHash[K]()
+}
+
+// RandomSeed - returns a random 64-bit seed from the crypto source
+func RandomSeed() uint64 {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// Mix64 - the splitmix64 finalizer: spreads the bits of a 64-bit value,
+// e.g. to derive a second, independent-looking hash from the first one
+func Mix64(x uint64) uint64 {
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+// appendKey - encodes a key into bytes that are fed to a hash function.
+// Common types are encoded directly, everything else through its Go-syntax representation.
+// Keys that are equal must give equal bytes: -0.0 is encoded as +0.0 (they compare equal).
+// NaN is encoded by its bits; it is never equal to itself, so, like in a Go map, a NaN key is never found again.
+func appendKey[K any](buf []byte, key K) []byte {
+	switch k := any(key).(type) {
+	case string:
+		return append(buf, k...)
+	case []byte:
+		return append(buf, k...)
+	case bool:
+		if k {
+			return append(buf, 1)
+		}
+		return append(buf, 0)
+	case int:
+		return binary.LittleEndian.AppendUint64(buf, uint64(k))
+	case int8:
+		return append(buf, byte(k))
+	case int16:
+		return binary.LittleEndian.AppendUint16(buf, uint16(k))
+	case int32:
+		return binary.LittleEndian.AppendUint32(buf, uint32(k))
+	case int64:
+		return binary.LittleEndian.AppendUint64(buf, uint64(k))
+	case uint:
+		return binary.LittleEndian.AppendUint64(buf, uint64(k))
+	case uint8:
+		return append(buf, k)
+	case uint16:
+		return binary.LittleEndian.AppendUint16(buf, k)
+	case uint32:
+		return binary.LittleEndian.AppendUint32(buf, k)
+	case uint64:
+		return binary.LittleEndian.AppendUint64(buf, k)
+	case uintptr:
+		return binary.LittleEndian.AppendUint64(buf, uint64(k))
+	case float32:
+		if k == 0 {
+			k = 0 // -0 -> +0
+		}
+		return binary.LittleEndian.AppendUint32(buf, math.Float32bits(k))
+	case float64:
+		if k == 0 {
+			k = 0 // -0 -> +0
+		}
+		return binary.LittleEndian.AppendUint64(buf, math.Float64bits(k))
+	default:
+		return fmt.Appendf(buf, "%#v", key)
+	}
+}
+
+// FNV1a - the 64-bit FNV-1a hash with an optional seed
+type FNV1a[K any] struct {
+	seed uint64
+}
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// NewFNV1a - creates an FNV-1a hasher with a random seed
+func NewFNV1a[K any]() *FNV1a[K] {
+	return &FNV1a[K]{seed: RandomSeed()}
+}
+
+// NewFNV1aWithSeed - creates an FNV-1a hasher with a fixed seed.
+// A zero seed gives the classic FNV-1a, the same as hash/fnv.New64a.
+func NewFNV1aWithSeed[K any](seed uint64) *FNV1a[K] {
+	return &FNV1a[K]{seed: seed}
+}
+
+// Hash - returns the FNV-1a hash of a key
+func (f *FNV1a[K]) Hash(key K) uint64 {
+	var buf [64]byte
+	return fnv1a(f.seed, appendKey(buf[:0], key))
+}
+
+// fnv1a - hashes the seed bytes (if any) and then the data
+func fnv1a(seed uint64, data []byte) uint64 {
+	h := uint64(fnvOffset64)
+
+	if seed != 0 {
+		for i := 0; i < 8; i++ {
+			h ^= (seed >> (8 * i)) & 0xff
+			h *= fnvPrime64
+		}
+	}
+
+	for _, b := range data {
+		h ^= uint64(b)
+		h *= fnvPrime64
+	}
+
+	return h
+}
+
+// XXHash64 - the 64-bit xxHash with a seed
+type XXHash64[K any] struct {
+	seed uint64
+}
+
+// NewXXHash64 - creates an xxHash64 hasher with a random seed
+func NewXXHash64[K any]() *XXHash64[K] {
+	return &XXHash64[K]{seed: RandomSeed()}
+}
+
+// NewXXHash64WithSeed - creates an xxHash64 hasher with a fixed seed
+func NewXXHash64WithSeed[K any](seed uint64) *XXHash64[K] {
+	return &XXHash64[K]{seed: seed}
+}
+
+// Hash - returns the xxHash64 of a key
+func (x *XXHash64[K]) Hash(key K) uint64 {
+	var buf [64]byte
+	return xxhash64(x.seed, appendKey(buf[:0], key))
+}
+
+// SipHash - the keyed SipHash-2-4 function (2 compression rounds, 4 finalization rounds)
+type SipHash[K any] struct {
+	k0, k1 uint64 // The 128-bit secret key
+}
+
+// NewSipHash - creates a SipHash hasher with a random 128-bit key
+func NewSipHash[K any]() *SipHash[K] {
+	return &SipHash[K]{k0: RandomSeed(), k1: RandomSeed()}
+}
+
+// NewSipHashWithKey - creates a SipHash hasher with a fixed key
+func NewSipHashWithKey[K any](k0, k1 uint64) *SipHash[K] {
+	return &SipHash[K]{k0: k0, k1: k1}
+}
+
+// Hash - returns the SipHash-2-4 of a key
+func (s *SipHash[K]) Hash(key K) uint64 {
+	var buf [64]byte
+	return siphash24(s.k0, s.k1, appendKey(buf[:0], key))
+}
+
+// MapHash - the runtime hash of the built-in map with a random seed
+type MapHash[K comparable] struct {
+	seed maphash.Seed
+}
+
+// NewMapHash - creates a MapHash hasher with a random seed
+func NewMapHash[K comparable]() *MapHash[K] {
+	return &MapHash[K]{seed: maphash.MakeSeed()}
+}
+
+// Hash - returns the runtime hash of a key
+func (m *MapHash[K]) Hash(key K) uint64 {
+	return maphash.Comparable(m.seed, key)
+}
+
+// Func - adapts an ordinary function to the Hasher interface
+type Func[K any] func(key K) uint64
+
+// Hash - calls the function
+func (f Func[K]) Hash(key K) uint64 {
+	return f(key)
+}
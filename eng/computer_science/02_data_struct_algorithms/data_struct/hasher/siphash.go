@@ -0,0 +1,68 @@
+package hasher
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// siphash24 - computes SipHash-2-4 of data with the key (k0, k1).
+// The state is four 64-bit words; each 8-byte block goes through 2 SipRounds,
+// and the result goes through 4 more SipRounds.
+func siphash24(k0, k1 uint64, data []byte) uint64 {
+	v0 := k0 ^ 0x736f6d6570736575 // "somepseu"
+	v1 := k1 ^ 0x646f72616e646f6d // "dorandom"
+	v2 := k0 ^ 0x6c7967656e657261 // "lygenera"
+	v3 := k1 ^ 0x7465646279746573 // "tedbytes"
+
+	n := len(data)
+
+	for len(data) >= 8 {
+		m := binary.LittleEndian.Uint64(data)
+		v3 ^= m
+		v0, v1, v2, v3 = sipRound(v0, v1, v2, v3)
+		v0, v1, v2, v3 = sipRound(v0, v1, v2, v3)
+		v0 ^= m
+		data = data[8:]
+	}
+
+	// The last block holds the remaining bytes and the message length in the top byte
+	last := uint64(n) << 56
+	for i, b := range data {
+		last |= uint64(b) << (8 * i)
+	}
+
+	v3 ^= last
+	v0, v1, v2, v3 = sipRound(v0, v1, v2, v3)
+	v0, v1, v2, v3 = sipRound(v0, v1, v2, v3)
+	v0 ^= last
+
+	v2 ^= 0xff
+	for i := 0; i < 4; i++ {
+		v0, v1, v2, v3 = sipRound(v0, v1, v2, v3)
+	}
+
+	return v0 ^ v1 ^ v2 ^ v3
+}
+
+// sipRound - one ARX (add-rotate-xor) round of SipHash
+func sipRound(v0, v1, v2, v3 uint64) (uint64, uint64, uint64, uint64) {
+	v0 += v1
+	v1 = bits.RotateLeft64(v1, 13)
+	v1 ^= v0
+	v0 = bits.RotateLeft64(v0, 32)
+
+	v2 += v3
+	v3 = bits.RotateLeft64(v3, 16)
+	v3 ^= v2
+
+	v0 += v3
+	v3 = bits.RotateLeft64(v3, 21)
+	v3 ^= v0
+
+	v2 += v1
+	v1 = bits.RotateLeft64(v1, 17)
+	v1 ^= v2
+	v2 = bits.RotateLeft64(v2, 32)
+
+	return v0, v1, v2, v3
+}
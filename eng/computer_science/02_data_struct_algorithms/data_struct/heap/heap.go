@@ -38,6 +38,12 @@ How to know if a problem fits Heap?
 - You need to find the "K largest/smallest" elements.
 - You need to constantly retrieve the current minimum/maximum from a dynamically changing data set.
 - The task involves merging K sorted arrays/lists.
+
+Heap[T] in this package:
+- A generic binary heap ordered by a less function (a min-heap for "<", a max-heap for ">").
+- It does not need the container/heap boilerplate (Len/Less/Swap/Push/Pop) for every element type.
+- NewIndexed reports every move of an element, so the owner can remember its position
+  and later update (Fix) or delete (Remove) an element in O(log n) — e.g. the expiry index of cache_ttl.
 */
 
 package heap
@@ -68,3 +74,125 @@ func (h *IntHeap) Pop() interface{} {
 	*h = old[0 : n-1]
 	return x
 }
+
+// Heap - a generic binary heap; the root is the element for which less is true against all others
+type Heap[T any] struct {
+	items []T
+	less  func(a, b T) bool
+	moved func(item T, i int) // Called with the new index of an element after every move (optional)
+}
+
+// New - creates an empty heap ordered by less
+func New[T any](less func(a, b T) bool) *Heap[T] {
+	return &Heap[T]{less: less}
+}
+
+// NewIndexed - creates an empty heap that reports the index of an element every time it changes
+func NewIndexed[T any](less func(a, b T) bool, moved func(item T, i int)) *Heap[T] {
+	return &Heap[T]{less: less, moved: moved}
+}
+
+// Len - returns the number of elements
+func (h *Heap[T]) Len() int {
+	return len(h.items)
+}
+
+// Peek - returns the root without removing it
+func (h *Heap[T]) Peek() (T, bool) {
+	if len(h.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.items[0], true
+}
+
+// Push - adds an element (O(log n))
+func (h *Heap[T]) Push(item T) {
+	h.items = append(h.items, item)
+	h.notify(len(h.items) - 1)
+	h.up(len(h.items) - 1)
+}
+
+// Pop - removes and returns the root (O(log n))
+func (h *Heap[T]) Pop() (T, bool) {
+	if len(h.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.Remove(0), true
+}
+
+// Remove - removes and returns the element at index i (O(log n))
+func (h *Heap[T]) Remove(i int) T {
+	last := len(h.items) - 1
+	if i != last {
+		h.swap(i, last)
+	}
+
+	item := h.items[last]
+	var zero T
+	h.items[last] = zero // Do not keep a reference to the removed element
+	h.items = h.items[:last]
+
+	if i != last {
+		h.Fix(i)
+	}
+	return item
+}
+
+// Fix - restores the order after the element at index i has changed (O(log n))
+func (h *Heap[T]) Fix(i int) {
+	if !h.down(i) {
+		h.up(i)
+	}
+}
+
+// up - sifts the element at index i towards the root
+func (h *Heap[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !h.less(h.items[i], h.items[parent]) {
+			break
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+}
+
+// down - sifts the element at index i towards the leaves, reports whether it moved
+func (h *Heap[T]) down(i int) bool {
+	start := i
+	n := len(h.items)
+
+	for {
+		smallest := i
+		left, right := 2*i+1, 2*i+2
+		if left < n && h.less(h.items[left], h.items[smallest]) {
+			smallest = left
+		}
+		if right < n && h.less(h.items[right], h.items[smallest]) {
+			smallest = right
+		}
+		if smallest == i {
+			break
+		}
+		h.swap(i, smallest)
+		i = smallest
+	}
+
+	return i > start
+}
+
+// swap - swaps two elements and reports their new positions
+func (h *Heap[T]) swap(i, j int) {
+	h.items[i], h.items[j] = h.items[j], h.items[i]
+	h.notify(i)
+	h.notify(j)
+}
+
+// notify - reports the position of the element at index i
+func (h *Heap[T]) notify(i int) {
+	if h.moved != nil {
+		h.moved(h.items[i], i)
+	}
+}
@@ -0,0 +1,68 @@
+package hyperloglog
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Example demonstrates the use of a HyperLogLog sketch
+func Example() {
+	// 1. Accuracy and encoding at different cardinalities (precision 14: 16 KB dense, error ~0.8%)
+	for _, n := range []int{100, 3000, 100000, 1000000} {
+		s := New[string]()
+		for i := range n {
+			user := "user:" + strconv.Itoa(i)
+			s.Add(user)
+			s.Add(user) // Duplicates do not change the estimate
+		}
+
+		data, _ := s.MarshalBinary()
+		encoding := "dense"
+		if s.Sparse() {
+			encoding = "sparse"
+		}
+		fmt.Printf("n = %7d: estimate %7d (error %5.2f%%), %s, %d bytes marshaled\n",
+			n, s.Count(), relativeError(s.Count(), n), encoding, len(data))
+	}
+
+	// 2. Distinct users per shard, merged into a global count.
+	// Every shard sees 30 000 users, neighbouring shards share 10 000 of them: 100 000 distinct users in total.
+	var shards [5][]byte
+	for shard := range shards {
+		s := New[string]()
+		for i := shard * 20000; i < shard*20000+30000; i++ {
+			s.Add("user:" + strconv.Itoa(i%100000))
+		}
+		shards[shard], _ = s.MarshalBinary() // Sent to the aggregator
+	}
+
+	total := New[string]()
+	for _, data := range shards {
+		var s Sketch[string]
+		if err := s.UnmarshalBinary(data); err != nil {
+			fmt.Println(err)
+			return
+		}
+		fmt.Printf("shard: ~%d users; ", s.Count())
+		total.Merge(&s)
+	}
+	fmt.Printf("\nall shards: ~%d distinct users (exact 100000, error %.2f%%)\n", total.Count(), relativeError(total.Count(), 100000))
+
+	// 3. Precision: more registers, less error
+	for _, p := range []int{8, 10, 12, 14, 16} {
+		s := New(WithPrecision[int](p), WithDense[int]())
+		for i := range 500000 {
+			s.Add(i)
+		}
+		fmt.Printf("p = %2d: %6d bytes, expected error %.2f%%, actual %.2f%%\n",
+			p, 1<<p, 104/math.Sqrt(float64(int(1)<<p)), relativeError(s.Count(), 500000))
+	}
+
+	fmt.Printf("Merge of different precisions: %v\n", New[int](WithPrecision[int](10)).Merge(New[int]()))
+}
+
+// relativeError - returns |estimate - exact| / exact in percent
+func relativeError(estimate uint64, exact int) float64 {
+	return math.Abs(float64(estimate)-float64(exact)) / float64(exact) * 100
+}
@@ -0,0 +1,488 @@
+/*
+HyperLogLog++ (cardinality estimation)
+
+What is it?
+HyperLogLog estimates the number of distinct elements in a stream (the cardinality) with a fixed, small amount
+of memory: 2^p tiny registers (one byte each here). 16 KB are enough to count a billion distinct users
+with an error of about 0.8%. HyperLogLog++ (Google, 2013) adds a 64-bit hash and a sparse encoding for small sets.
+
+Why is it needed?
+- An exact count (hash_table.CountFrequency, ContainsDuplicate, a map of seen ids) needs memory
+  proportional to the number of distinct values: millions of users — tens of megabytes per counter.
+- Sketches of different shards or time windows can be merged: the distinct users of a day
+  are the merge of the hourly sketches, without keeping the user ids.
+
+What's the point?
+- The key is hashed to 64 bits. The first p bits choose one of m = 2^p registers.
+- In the remaining bits we look at the position of the first 1 (rho = number of leading zeros + 1).
+  A value with rho = k appears once in about 2^k distinct values, so the maximum rho seen by a register
+  tells roughly log2 of the number of distinct values that fell into it.
+- The estimate combines all registers (a harmonic mean), which reduces the error to about 1.04/sqrt(m).
+- Duplicates do not change the registers, so the sketch counts distinct values only.
+
+When to use?
+- Distinct users, IPs, search queries per shard, per day, per page — when ±1% is good enough.
+- When the counts must be combined across machines or time windows (Merge).
+- Not when the exact number is needed, or the set itself is needed (use a map or a Bloom filter).
+
+How does it work?
+- Precision p is configurable (WithPrecision, 4..18, 14 by default): m = 2^p registers, error 1.04/sqrt(m).
+- Sparse encoding (HyperLogLog++): while the set is small, most registers are zero, so instead of the registers
+  a sorted list of (index, rho) pairs is kept, computed with a higher precision of 25 bits.
+  It takes 4 bytes per distinct value, and the estimate is linear counting over 2^25 buckets — almost exact.
+  New pairs go to a small unsorted buffer first and are merged into the list in batches.
+  When the list would take more memory than the dense registers, it is converted to them.
+- Dense estimate: instead of the empirical bias tables of HyperLogLog++, the sketch uses the improved estimator
+  of O. Ertl (2017, also used by Redis). It is unbiased over the whole range, from a few values to billions.
+- Merge takes the maximum of every register (or the union of the sparse lists), so merging sketches
+  of two shards gives exactly the sketch of all their values.
+- MarshalBinary / UnmarshalBinary: the sparse list is stored as varint deltas, the dense registers as bytes.
+- Keys are hashed with a hasher.Hasher; the default is xxHash64 with a fixed seed, so sketches built
+  in different processes can be merged.
+
+### Complexity
+
+| Operation | Time (O) | Space (O) |
+|:---|:---:|:---:|
+| Add | O(1) amortized | O(1) |
+| Count | O(m) (dense), O(s log s) (sparse) | O(1) |
+| Merge | O(m) | O(m) |
+| Memory | — | m bytes (dense), 4 bytes per value (sparse) |
+
+*m = 2^p registers, s — number of sparse pairs.
+
+Examples of using HyperLogLog:
+See the example.go file.
+*/
+
+package hyperloglog
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"slices"
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/hasher"
+)
+
+var (
+	// ErrPrecision - the sketches have different precisions
+	ErrPrecision = errors.New("hyperloglog: sketches have different precisions")
+	// ErrFormat - the binary data is not a marshaled sketch
+	ErrFormat = errors.New("hyperloglog: invalid binary format")
+)
+
+const (
+	minPrecision     = 4
+	maxPrecision     = 18
+	defaultPrecision = 14
+	sparsePrecision  = 25 // Precision of the sparse pairs
+	formatVersion    = 1
+)
+
+// Sketch - a HyperLogLog++ sketch, safe for concurrent use
+type Sketch[K any] struct {
+	p         uint8
+	m         uint32   // Number of registers: 2^p
+	sparse    bool     // The sketch is in the sparse encoding
+	list      []uint32 // Sorted sparse pairs: index at 25 bits << 6 | rho
+	buf       []uint32 // Unsorted sparse pairs not merged into the list yet
+	registers []uint8  // Dense registers (nil while sparse)
+	hasher    hasher.Hasher[K]
+	mu        *sync.Mutex
+}
+
+// Option - configures a sketch
+type Option[K any] func(*Sketch[K])
+
+// WithPrecision - sets the number of index bits p, 4..18 (14 by default): 2^p registers, error 1.04/sqrt(2^p)
+func WithPrecision[K any](p int) Option[K] {
+	return func(s *Sketch[K]) {
+		s.p = uint8(min(max(p, minPrecision), maxPrecision))
+	}
+}
+
+// WithHasher - sets the hash function of the keys (xxHash64 with seed 0 by default).
+// Only sketches with the same hasher can be merged.
+func WithHasher[K any](h hasher.Hasher[K]) Option[K] {
+	return func(s *Sketch[K]) {
+		if h != nil {
+			s.hasher = h
+		}
+	}
+}
+
+// WithDense - starts the sketch with the dense registers, skipping the sparse encoding
+func WithDense[K any]() Option[K] {
+	return func(s *Sketch[K]) {
+		s.sparse = false
+	}
+}
+
+// New - creates an empty sketch
+func New[K any](opts ...Option[K]) *Sketch[K] {
+	s := &Sketch[K]{
+		p:      defaultPrecision,
+		sparse: true,
+		hasher: hasher.NewXXHash64WithSeed[K](0),
+		mu:     &sync.Mutex{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.m = 1 << s.p
+	if !s.sparse {
+		s.registers = make([]uint8, s.m)
+	}
+	return s
+}
+
+// Add - adds a value to the set
+func (s *Sketch[K]) Add(key K) {
+	h := s.hasher.Hash(key)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.sparse {
+		s.addDense(h)
+		return
+	}
+
+	s.buf = append(s.buf, encodeSparse(h))
+	if len(s.buf) >= s.bufLimit() {
+		s.flush()
+	}
+}
+
+// Count - returns the estimated number of distinct values
+func (s *Sketch[K]) Count() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sparse {
+		s.flush()
+	}
+	if s.sparse {
+		// Linear counting over the 2^25 buckets of the sparse pairs
+		m := float64(uint64(1) << sparsePrecision)
+		return uint64(math.Round(m * math.Log(m/(m-float64(len(s.list))))))
+	}
+	return uint64(math.Round(estimate(s.registers, s.p)))
+}
+
+// Merge - adds all values of another sketch. Both sketches must have the same precision and hasher.
+func (s *Sketch[K]) Merge(other *Sketch[K]) error {
+	if s.p != other.p {
+		return ErrPrecision
+	}
+	if s == other {
+		return nil
+	}
+
+	other.mu.Lock()
+	if other.sparse {
+		other.flush()
+	}
+	sparse := other.sparse
+	list := slices.Clone(other.list)
+	registers := slices.Clone(other.registers)
+	other.mu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sparse {
+		s.flush()
+	}
+
+	switch {
+	case s.sparse && sparse:
+		s.list = mergeSparse(s.list, list)
+		if s.tooLarge() {
+			s.toDense()
+		}
+	default:
+		if s.sparse {
+			s.toDense()
+		}
+		for _, e := range list {
+			s.addSparseToDense(e)
+		}
+		for i, r := range registers {
+			s.registers[i] = max(s.registers[i], r)
+		}
+	}
+	return nil
+}
+
+// Precision - returns the number of index bits p
+func (s *Sketch[K]) Precision() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int(s.p)
+}
+
+// Sparse - reports whether the sketch is still in the sparse encoding
+func (s *Sketch[K]) Sparse() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sparse
+}
+
+// Reset - removes all values and returns to the sparse encoding
+func (s *Sketch[K]) Reset() {
+	s.mu.Lock()
+	s.sparse = true
+	s.list, s.buf, s.registers = nil, nil, nil
+	s.mu.Unlock()
+}
+
+// addDense - updates the register of a hash (s.mu must be held)
+func (s *Sketch[K]) addDense(h uint64) {
+	idx := h >> (64 - s.p)
+	// The guard bit limits rho to 64-p+1 when all the remaining bits are zero
+	rho := uint8(bits.LeadingZeros64(h<<s.p|1<<(s.p-1))) + 1
+	s.registers[idx] = max(s.registers[idx], rho)
+}
+
+// encodeSparse - turns a hash into a sparse pair: the index at 25 bits and rho of the remaining 39 bits
+func encodeSparse(h uint64) uint32 {
+	idx := uint32(h >> (64 - sparsePrecision))
+	rho := uint32(bits.LeadingZeros64(h<<sparsePrecision|1<<(sparsePrecision-1))) + 1
+	return idx<<6 | rho
+}
+
+// addSparseToDense - updates the register of a sparse pair, converting its index and rho to the precision p
+// (s.mu must be held). The bits between p and 25 are the beginning of the dense rho:
+// if one of them is set, it ends there; otherwise the sparse rho continues it.
+func (s *Sketch[K]) addSparseToDense(e uint32) {
+	idx, rho := e>>6, uint8(e&63)
+	extra := sparsePrecision - uint32(s.p)
+
+	low := idx & (1<<extra - 1)
+	if low != 0 {
+		rho = uint8(extra-uint32(bits.Len32(low))) + 1
+	} else {
+		rho += uint8(extra)
+	}
+
+	i := idx >> extra
+	s.registers[i] = max(s.registers[i], rho)
+}
+
+// bufLimit - returns the size of the unsorted buffer that triggers a merge into the sorted list
+func (s *Sketch[K]) bufLimit() int {
+	return max(int(s.m)/16, 16)
+}
+
+// tooLarge - reports whether the sparse list takes more memory than the dense registers
+func (s *Sketch[K]) tooLarge() bool {
+	return len(s.list)*4 > int(s.m)
+}
+
+// flush - merges the buffer into the sorted list and converts to the dense registers if the list is too large
+// (s.mu must be held)
+func (s *Sketch[K]) flush() {
+	if len(s.buf) == 0 {
+		return
+	}
+
+	slices.Sort(s.buf)
+	s.list = mergeSparse(s.list, s.buf)
+	s.buf = s.buf[:0]
+
+	if s.tooLarge() {
+		s.toDense()
+	}
+}
+
+// toDense - converts the sparse pairs into the dense registers (s.mu must be held)
+func (s *Sketch[K]) toDense() {
+	s.registers = make([]uint8, s.m)
+	for _, e := range s.list {
+		s.addSparseToDense(e)
+	}
+	for _, e := range s.buf {
+		s.addSparseToDense(e)
+	}
+	s.sparse = false
+	s.list, s.buf = nil, nil
+}
+
+// mergeSparse - merges two sorted lists of sparse pairs, keeping the largest rho for every index.
+// Pairs of one index are adjacent and sorted by rho, because the index is in the high bits.
+func mergeSparse(a, b []uint32) []uint32 {
+	out := make([]uint32, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		var e uint32
+		if j == len(b) || i < len(a) && a[i] <= b[j] {
+			e, i = a[i], i+1
+		} else {
+			e, j = b[j], j+1
+		}
+
+		if n := len(out); n > 0 && out[n-1]>>6 == e>>6 {
+			out[n-1] = e // The same index: e has a larger or equal rho
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// estimate - the improved raw estimator of Ertl: no bias tables and no switch to linear counting are needed
+func estimate(registers []uint8, p uint8) float64 {
+	q := 64 - int(p) // Registers take values 0..q+1
+	counts := make([]float64, q+2)
+	for _, r := range registers {
+		counts[r]++
+	}
+
+	m := float64(len(registers))
+	z := m * tau(1-counts[q+1]/m)
+	for k := q; k >= 1; k-- {
+		z = 0.5 * (z + counts[k])
+	}
+	z += m * sigma(counts[0]/m)
+
+	return m * m / (2 * math.Ln2 * z)
+}
+
+// sigma - the series x + sum(x^(2^k) * 2^(k-1)) of the estimator
+func sigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+
+	y, z := 1.0, x
+	for {
+		x *= x
+		prev := z
+		z += x * y
+		y += y
+		if z == prev {
+			return z
+		}
+	}
+}
+
+// tau - the series (1 - x - sum((1 - x^(2^-k))^2 * 2^-k)) / 3 of the estimator
+func tau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+
+	y, z := 1.0, 1-x
+	for {
+		x = math.Sqrt(x)
+		prev := z
+		y *= 0.5
+		z -= (1 - x) * (1 - x) * y
+		if z == prev {
+			return z / 3
+		}
+	}
+}
+
+// MarshalBinary - encodes the sketch: "HLLP", version, precision, encoding (0 - sparse, 1 - dense)
+// and then the sparse pairs as varint deltas or the registers as bytes
+func (s *Sketch[K]) MarshalBinary() ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.sparse {
+		s.flush()
+	}
+
+	buf := append([]byte("HLLP"), formatVersion, s.p)
+	if !s.sparse {
+		buf = append(buf, 1)
+		return append(buf, s.registers...), nil
+	}
+
+	buf = append(buf, 0)
+	buf = binary.AppendUvarint(buf, uint64(len(s.list)))
+	prev := uint32(0)
+	for _, e := range s.list {
+		buf = binary.AppendUvarint(buf, uint64(e-prev)) // The list is sorted, so the deltas are small
+		prev = e
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary - replaces the sketch with an encoded one (the hasher is not encoded and must be the same)
+func (s *Sketch[K]) UnmarshalBinary(data []byte) error {
+	if len(data) < 7 || string(data[:4]) != "HLLP" || data[4] != formatVersion {
+		return ErrFormat
+	}
+
+	p, encoding, payload := data[5], data[6], data[7:]
+	if p < minPrecision || p > maxPrecision {
+		return ErrFormat
+	}
+	m := uint32(1) << p
+
+	var list []uint32
+	var registers []uint8
+	switch encoding {
+	case 0:
+		n, read := binary.Uvarint(payload)
+		if read <= 0 || n > uint64(len(payload)) {
+			return ErrFormat
+		}
+		payload = payload[read:]
+
+		list = make([]uint32, 0, n)
+		prev := uint64(0)
+		for range n {
+			delta, read := binary.Uvarint(payload)
+			if read <= 0 || prev+delta > math.MaxUint32 || len(list) > 0 && delta == 0 {
+				return ErrFormat
+			}
+			payload = payload[read:]
+			prev += delta
+			if prev>>6 >= 1<<sparsePrecision || prev&63 == 0 || prev&63 > 64-sparsePrecision+1 {
+				return ErrFormat // The index or rho is out of range
+			}
+			list = append(list, uint32(prev))
+		}
+		if len(payload) != 0 {
+			return ErrFormat
+		}
+	case 1:
+		if len(payload) != int(m) {
+			return ErrFormat
+		}
+		registers = slices.Clone(payload)
+		for _, r := range registers {
+			if int(r) > 64-int(p)+1 {
+				return ErrFormat
+			}
+		}
+	default:
+		return ErrFormat
+	}
+
+	if s.mu == nil {
+		s.mu = &sync.Mutex{} // A zero Sketch (e.g. a field being decoded)
+	}
+
+	s.mu.Lock()
+	s.p, s.m = p, m
+	s.sparse = encoding == 0
+	s.list, s.buf, s.registers = list, nil, registers
+	if s.hasher == nil {
+		s.hasher = hasher.NewXXHash64WithSeed[K](0)
+	}
+	s.mu.Unlock()
+
+	return nil
+}
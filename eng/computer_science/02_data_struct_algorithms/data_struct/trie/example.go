@@ -1,6 +1,9 @@
 package trie
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+)
 
 // Example demonstrates the use of a Trie
 func Example() {
@@ -26,6 +29,16 @@ func Example() {
 		startsWith := trie.StartsWith(prefix)
 		fmt.Printf("Are there words with prefix '%s'? %t\n", prefix, startsWith)
 	}
+
+	// List the words with a prefix (sorted for a stable output)
+	found := trie.WithPrefix("app")
+	sort.Strings(found)
+	fmt.Printf("Words with prefix 'app': %v\n", found)
+
+	// Delete a word: the shorter "app" stays, the nodes used only by "application" are pruned
+	trie.Delete("application")
+	fmt.Printf("After Delete(application): app %t, application %t, prefix 'appli' %t\n",
+		trie.Search("app"), trie.Search("application"), trie.StartsWith("appli"))
 }
 
 // Task: Longest Word in Dictionary
@@ -22,10 +22,13 @@ When to use:
 | Insertion | O(m) | O(m) |
 | Word Search | O(m) | O(1) |
 | Prefix Search | O(m) | O(1) |
-| Deletion | O(m) | O(1) |
+| Deletion | O(m) | O(m) |
+| Words with a prefix | O(m + k) | O(k) |
 | Storage | — | O(ALPHABET_SIZE * N * M) |
 
-*m — word length.
+*m — word length, k — total length of the found words.
+Words are walked by runes: bytes that are not valid UTF-8 become U+FFFD, so such words may collide.
+Encode arbitrary byte strings first (e.g. one byte per rune, see cache/invalidation).
 **N — number of words, M — average word length, ALPHABET_SIZE — alphabet size.
 */
 
@@ -87,3 +90,59 @@ func (t *Trie) StartsWith(prefix string) bool {
 	}
 	return true
 }
+
+// Delete - removes a word, pruning the nodes that no longer lead to any word; reports whether it was present
+func (t *Trie) Delete(word string) bool {
+	// The path from the root, to prune it bottom-up
+	path := []*TrieNode{t.root}
+	chars := []rune(word)
+
+	node := t.root
+	for _, char := range chars {
+		next, exists := node.children[char]
+		if !exists {
+			return false
+		}
+		node = next
+		path = append(path, node)
+	}
+	if !node.isEnd {
+		return false
+	}
+	node.isEnd = false
+
+	for i := len(chars) - 1; i >= 0; i-- {
+		child := path[i+1]
+		if child.isEnd || len(child.children) > 0 {
+			break
+		}
+		delete(path[i].children, chars[i])
+	}
+	return true
+}
+
+// WithPrefix - returns all words that start with the prefix (in no particular order)
+func (t *Trie) WithPrefix(prefix string) []string {
+	node := t.root
+	for _, char := range prefix {
+		next, exists := node.children[char]
+		if !exists {
+			return nil
+		}
+		node = next
+	}
+
+	var words []string
+	var collect func(node *TrieNode, word []rune)
+	collect = func(node *TrieNode, word []rune) {
+		if node.isEnd {
+			words = append(words, string(word))
+		}
+		for char, child := range node.children {
+			collect(child, append(word, char))
+		}
+	}
+	collect(node, []rune(prefix))
+
+	return words
+}
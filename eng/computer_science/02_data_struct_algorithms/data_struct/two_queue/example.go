@@ -0,0 +1,36 @@
+package two_queue
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// Example demonstrates the use of a 2Q cache
+func Example() {
+	ctx := context.Background()
+
+	// Capacity 8: A1in gives way to Am beyond 2 keys, A1out remembers 4 ghost keys
+	twoQueue := New(8, WithOnEvict(func(key string, value int) {
+		fmt.Printf("  evicted %s=%d\n", key, value)
+	}))
+
+	// The hot key is set, pushed out of A1in by other keys and requested again: it moves to Am
+	twoQueue.Set(ctx, "hot", 1)
+	for i := 0; i < 8; i++ {
+		twoQueue.Set(ctx, "warmup:"+strconv.Itoa(i), i)
+	}
+	twoQueue.Set(ctx, "hot", 1) // A ghost hit in A1out - straight into Am
+
+	// A scan only churns A1in, the key in Am survives
+	fmt.Println("scan of 6 keys:")
+	for i := 0; i < 6; i++ {
+		twoQueue.Set(ctx, "scan:"+strconv.Itoa(i), i)
+	}
+
+	value, err := twoQueue.Get(ctx, "hot")
+	fmt.Printf("Get(hot) after the scan: %d, hit=%t\n", value, err == nil)
+
+	stats := twoQueue.Stats()
+	fmt.Printf("Len: %d, hits: %d, misses: %d\n", twoQueue.Len(), stats.Hits, stats.Misses)
+}
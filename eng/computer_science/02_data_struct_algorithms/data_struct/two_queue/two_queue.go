@@ -0,0 +1,311 @@
+/*
+2Q Cache (Two Queue)
+
+What is it?
+2Q is a scan-resistant eviction policy. A key has to prove that it is used more than once
+before it is allowed into the main LRU queue.
+
+Why is it needed?
+- In LRU a one-off key immediately becomes "most recently used" and pushes a useful key out.
+- A scan of many one-off keys evicts the whole working set.
+- 2Q keeps one-off keys in a small separate queue, so they are evicted before the hot keys are touched.
+
+What's the point?
+- A1in — a small FIFO queue for keys seen for the first time (about 25% of the capacity).
+- A1out — a ghost FIFO queue that remembers only the keys recently evicted from A1in (about 50% of the capacity).
+- Am — the main LRU queue for keys that were seen again after leaving A1in.
+
+When to use?
+- Database buffer pools and page caches where scans are common.
+- When a simpler alternative to ARC is enough: 2Q has fixed queue sizes instead of adaptation.
+
+How does it work?
+- Get of a key in Am moves it to the front of Am; Get of a key in A1in is a hit but does not move it
+  (correlated accesses right after the first one do not prove popularity).
+- Set of a new key puts it into A1in.
+- Set of a key remembered in A1out means it was requested again after being evicted: it goes to Am.
+- When the cache is full: if A1in is over its size, its oldest key is evicted and remembered in A1out,
+  otherwise the least recently used key of Am is evicted. A1out drops its oldest keys beyond its size.
+- All three queues are lru.List from data_struct/LRU, one hash table points to the key in any of them.
+
+### Complexity
+
+| Operation | Time Complexity (O) | Space Complexity (O) |
+|:---|:---:|:---:|
+| Get | O(1) | O(1) |
+| Set (insertion/update) | O(1) | O(1) |
+| Deletion | O(1) | O(1) |
+| Storage | — | O(c) values + O(c) ghost keys |
+
+*c — the capacity of the cache.
+
+How to know if a problem fits 2Q?
+- Many keys are requested only once, and they must not evict the keys that are requested again and again.
+
+Cache[K, V] implements the common cache.Cache interface (see data_struct/cache) and is safe for concurrent use.
+
+Examples of using 2Q:
+See the example.go file.
+*/
+
+package two_queue
+
+import (
+	"context"
+	"sync"
+
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/LRU"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache"
+	"github.com/IBraveMonkey/data_structure-algorithms/eng/computer_science/02_data_struct_algorithms/data_struct/cache/stats"
+)
+
+const (
+	defaultRecentRatio = 0.25 // Share of the capacity for A1in
+	defaultGhostRatio  = 0.5  // Share of the capacity for A1out
+)
+
+// queue - the queue an entry belongs to
+type queue int
+
+const (
+	a1in  queue = iota // Cached, seen once, FIFO
+	a1out              // Ghost, evicted from a1in, FIFO
+	am                 // Cached, seen again, LRU
+)
+
+// entry - a key with its value; ghost entries keep only the key
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+	queue queue
+}
+
+// Cache - a 2Q cache
+type Cache[K comparable, V any] struct {
+	mu        sync.Mutex
+	capacity  int
+	inSize    int                              // Max size of a1in before it gives way to am
+	outSize   int                              // Max size of a1out
+	data      map[K]*lru.Element[*entry[K, V]] // Key -> element of one of the queues
+	queues    [3]*lru.List[*entry[K, V]]       // a1in, a1out, am; front - the newest
+	onEvict   func(key K, value V)
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+var _ cache.Cache[string, int] = (*Cache[string, int])(nil)
+
+// options - 2Q settings
+type options[K comparable, V any] struct {
+	recentRatio float64
+	ghostRatio  float64
+	onEvict     func(key K, value V)
+}
+
+// Option - configures a 2Q cache
+type Option[K comparable, V any] func(*options[K, V])
+
+// WithRecentRatio - sets the share of the capacity for A1in (0.25 by default)
+func WithRecentRatio[K comparable, V any](ratio float64) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.recentRatio = ratio
+	}
+}
+
+// WithGhostRatio - sets the number of ghost keys in A1out as a share of the capacity (0.5 by default)
+func WithGhostRatio[K comparable, V any](ratio float64) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.ghostRatio = ratio
+	}
+}
+
+// WithOnEvict - sets a callback for entries evicted because of the capacity
+func WithOnEvict[K comparable, V any](fn func(key K, value V)) Option[K, V] {
+	return func(o *options[K, V]) {
+		o.onEvict = fn
+	}
+}
+
+// New - creates a new 2Q cache with a given capacity (at least 1)
+func New[K comparable, V any](capacity int, opts ...Option[K, V]) *Cache[K, V] {
+	o := options[K, V]{
+		recentRatio: defaultRecentRatio,
+		ghostRatio:  defaultGhostRatio,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	capacity = max(capacity, 1)
+
+	c := &Cache[K, V]{
+		capacity: capacity,
+		inSize:   max(int(float64(capacity)*o.recentRatio), 1),
+		outSize:  max(int(float64(capacity)*o.ghostRatio), 1),
+		data:     make(map[K]*lru.Element[*entry[K, V]], 2*capacity),
+		onEvict:  o.onEvict,
+	}
+	for i := range c.queues {
+		c.queues[i] = lru.NewList[*entry[K, V]]()
+	}
+
+	return c
+}
+
+// Get - returns the value by key; only a hit in Am changes the order
+func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	var zero V
+	if err := ctx.Err(); err != nil {
+		return zero, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.data[key]
+	if !ok || el.Value.queue == a1out {
+		c.misses++
+		return zero, cache.ErrNotFound
+	}
+
+	c.hits++
+	if el.Value.queue == am {
+		c.queues[am].MoveToFront(el)
+	}
+	return el.Value.value, nil
+}
+
+// Peek - returns the value by key without changing the queues
+func (c *Cache[K, V]) Peek(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.data[key]; ok && el.Value.queue != a1out {
+		return el.Value.value, true
+	}
+
+	var zero V
+	return zero, false
+}
+
+// Set - adds or updates an item; a key remembered in A1out goes straight to Am
+func (c *Cache[K, V]) Set(ctx context.Context, key K, value V) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+
+	var victim *entry[K, V]
+	el, ok := c.data[key]
+
+	switch {
+	case ok && el.Value.queue == am:
+		el.Value.value = value
+		c.queues[am].MoveToFront(el)
+
+	case ok && el.Value.queue == a1in:
+		el.Value.value = value
+
+	case ok: // a1out
+		c.queues[a1out].Remove(el)
+		delete(c.data, key)
+		victim = c.reclaim()
+		el.Value.value = value
+		el.Value.queue = am
+		c.data[key] = c.queues[am].PushFront(el.Value)
+
+	default:
+		victim = c.reclaim()
+		c.data[key] = c.queues[a1in].PushFront(&entry[K, V]{key: key, value: value, queue: a1in})
+	}
+
+	c.mu.Unlock()
+
+	if victim != nil && c.onEvict != nil {
+		c.onEvict(victim.key, victim.value)
+	}
+	return nil
+}
+
+// Delete - removes an item from the cache
+func (c *Cache[K, V]) Delete(ctx context.Context, key K) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	c.Remove(key)
+	return nil
+}
+
+// Remove - removes an item and its ghost, reports whether the item was cached (OnEvict is not called)
+func (c *Cache[K, V]) Remove(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.data[key]
+	if !ok {
+		return false
+	}
+
+	cached := el.Value.queue != a1out
+	c.queues[el.Value.queue].Remove(el)
+	delete(c.data, key)
+
+	return cached
+}
+
+// Len - returns the number of cached items (ghosts are not counted)
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.queues[a1in].Len() + c.queues[am].Len()
+}
+
+// Stats - returns the hit, miss and eviction counters and the size (ghosts are not counted)
+func (c *Cache[K, V]) Stats() stats.Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return stats.Stats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Size:      c.queues[a1in].Len() + c.queues[am].Len(),
+	}
+}
+
+// reclaim - frees a place for one more item if the cache is full, returns the evicted entry (c.mu must be held)
+func (c *Cache[K, V]) reclaim() *entry[K, V] {
+	if c.queues[a1in].Len()+c.queues[am].Len() < c.capacity {
+		return nil
+	}
+	c.evictions++
+
+	if c.queues[a1in].Len() > c.inSize || c.queues[am].Len() == 0 {
+		// The oldest key of A1in leaves the cache but is remembered in A1out
+		el := c.queues[a1in].Back()
+		victim := *el.Value
+
+		var zero V
+		el.Value.value = zero
+		el.Value.queue = a1out
+		c.queues[a1in].Remove(el)
+		c.data[victim.key] = c.queues[a1out].PushFront(el.Value)
+
+		for c.queues[a1out].Len() > c.outSize {
+			ghost := c.queues[a1out].Remove(c.queues[a1out].Back())
+			delete(c.data, ghost.key)
+		}
+
+		return &victim
+	}
+
+	// Otherwise the least recently used key of Am is evicted for good
+	victim := c.queues[am].Remove(c.queues[am].Back())
+	delete(c.data, victim.key)
+
+	return victim
+}